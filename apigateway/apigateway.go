@@ -0,0 +1,241 @@
+// Package apigateway adapts AWS API Gateway proxy events to pagination.
+//
+// The request and response types have the same JSON shape as the Lambda proxy
+// integration events, so they can be used as a Lambda handler's input and output
+// without depending on the AWS SDK.
+package apigateway
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// ProxyRequest is an API Gateway REST API (payload format 1.0) proxy request.
+type ProxyRequest struct {
+	Resource                        string              `json:"resource"`
+	Path                            string              `json:"path"`
+	HTTPMethod                      string              `json:"httpMethod"`
+	Headers                         map[string]string   `json:"headers"`
+	MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+	QueryStringParameters           map[string]string   `json:"queryStringParameters"`
+	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+	PathParameters                  map[string]string   `json:"pathParameters"`
+	Body                            string              `json:"body"`
+	IsBase64Encoded                 bool                `json:"isBase64Encoded,omitempty"`
+}
+
+// ProxyResponse is an API Gateway REST API (payload format 1.0) proxy response.
+type ProxyResponse struct {
+	StatusCode        int                 `json:"statusCode"`
+	Headers           map[string]string   `json:"headers"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded,omitempty"`
+}
+
+// V2HTTPRequest is an API Gateway HTTP API (payload format 2.0) request.
+type V2HTTPRequest struct {
+	Version               string               `json:"version"`
+	RouteKey              string               `json:"routeKey"`
+	RawPath               string               `json:"rawPath"`
+	RawQueryString        string               `json:"rawQueryString"`
+	Cookies               []string             `json:"cookies,omitempty"`
+	Headers               map[string]string    `json:"headers"`
+	QueryStringParameters map[string]string    `json:"queryStringParameters,omitempty"`
+	PathParameters        map[string]string    `json:"pathParameters,omitempty"`
+	RequestContext        V2HTTPRequestContext `json:"requestContext"`
+	Body                  string               `json:"body,omitempty"`
+	IsBase64Encoded       bool                 `json:"isBase64Encoded"`
+}
+
+// V2HTTPRequestContext is the request context of V2HTTPRequest.
+type V2HTTPRequestContext struct {
+	HTTP V2HTTPRequestContextHTTP `json:"http"`
+}
+
+// V2HTTPRequestContextHTTP has the HTTP method and path of V2HTTPRequest.
+type V2HTTPRequestContextHTTP struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// V2HTTPResponse is an API Gateway HTTP API (payload format 2.0) response.
+type V2HTTPResponse struct {
+	StatusCode        int                 `json:"statusCode"`
+	Headers           map[string]string   `json:"headers"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded,omitempty"`
+	Cookies           []string            `json:"cookies"`
+}
+
+// ParseRequest parses the proxy request to get limit, page and sort with pagination.DefaultParser.
+func ParseRequest(req *ProxyRequest) (*pagination.Query, error) {
+	r, err := req.HTTPRequest()
+	if err != nil {
+		return nil, err
+	}
+	return pagination.DefaultParser.ParseRequest(r)
+}
+
+// ParseV2Request parses the HTTP API request to get limit, page and sort with pagination.DefaultParser.
+func ParseV2Request(req *V2HTTPRequest) (*pagination.Query, error) {
+	r, err := req.HTTPRequest()
+	if err != nil {
+		return nil, err
+	}
+	return pagination.DefaultParser.ParseRequest(r)
+}
+
+// HTTPRequest converts the proxy request to *http.Request, to be parsed by pagination.Parser.
+// Multi-value query parameters and headers are used if present.
+func (req *ProxyRequest) HTTPRequest() (*http.Request, error) {
+	query := url.Values{}
+	if len(req.MultiValueQueryStringParameters) > 0 {
+		for k, vs := range req.MultiValueQueryStringParameters {
+			query[k] = append([]string{}, vs...)
+		}
+	} else {
+		for k, v := range req.QueryStringParameters {
+			query.Set(k, v)
+		}
+	}
+
+	header := http.Header{}
+	for k, v := range req.Headers {
+		header.Set(k, v)
+	}
+	for k, vs := range req.MultiValueHeaders {
+		header.Del(k)
+		for _, v := range vs {
+			header.Add(k, v)
+		}
+	}
+
+	return newHTTPRequest(req.HTTPMethod, req.Path, query, header, req.Body, req.IsBase64Encoded)
+}
+
+// HTTPRequest converts the HTTP API request to *http.Request, to be parsed by pagination.Parser.
+// Repeated query parameters are read from the raw query string.
+func (req *V2HTTPRequest) HTTPRequest() (*http.Request, error) {
+	query, err := url.ParseQuery(req.RawQueryString)
+	if err != nil {
+		return nil, err
+	}
+	if req.RawQueryString == "" {
+		for k, v := range req.QueryStringParameters {
+			query.Set(k, v)
+		}
+	}
+
+	header := http.Header{}
+	for k, v := range req.Headers {
+		header.Set(k, v)
+	}
+
+	path := req.RawPath
+	if path == "" {
+		path = req.RequestContext.HTTP.Path
+	}
+
+	return newHTTPRequest(req.RequestContext.HTTP.Method, path, query, header, req.Body, req.IsBase64Encoded)
+}
+
+func newHTTPRequest(method, path string, query url.Values, header http.Header, body string, isBase64Encoded bool) (*http.Request, error) {
+	b := []byte(body)
+	if isBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return nil, err
+		}
+		b = decoded
+	}
+
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	return &http.Request{
+		Method:        method,
+		URL:           &url.URL{Path: path, RawQuery: query.Encode()},
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(b)),
+		ContentLength: int64(len(b)),
+	}, nil
+}
+
+// NewResponse returns a proxy response of the result of pagination.Fetch.
+// X-Total-Count and X-Total-Pages headers are set.
+func NewResponse(totalCount, pageCount int, res *pagination.PagingResponse) (*ProxyResponse, error) {
+	body, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return &ProxyResponse{
+		StatusCode: http.StatusOK,
+		Headers:    pagingHeaders(totalCount, pageCount),
+		Body:       string(body),
+	}, nil
+}
+
+// NewV2Response returns an HTTP API response of the result of pagination.Fetch.
+// X-Total-Count and X-Total-Pages headers are set.
+func NewV2Response(totalCount, pageCount int, res *pagination.PagingResponse) (*V2HTTPResponse, error) {
+	body, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return &V2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Headers:    pagingHeaders(totalCount, pageCount),
+		Body:       string(body),
+	}, nil
+}
+
+// NewErrorResponse returns a proxy response with a JSON error message.
+// The message of a server error (5xx) is the status text, like pagination.WriteError.
+func NewErrorResponse(statusCode int, err error) *ProxyResponse {
+	return &ProxyResponse{
+		StatusCode: statusCode,
+		Headers:    map[string]string{"Content-Type": "application/json; charset=utf-8"},
+		Body:       errorBody(statusCode, err),
+	}
+}
+
+// NewV2ErrorResponse returns an HTTP API response with a JSON error message.
+// The message of a server error (5xx) is the status text, like pagination.WriteError.
+func NewV2ErrorResponse(statusCode int, err error) *V2HTTPResponse {
+	return &V2HTTPResponse{
+		StatusCode: statusCode,
+		Headers:    map[string]string{"Content-Type": "application/json; charset=utf-8"},
+		Body:       errorBody(statusCode, err),
+	}
+}
+
+func pagingHeaders(totalCount, pageCount int) map[string]string {
+	return map[string]string{
+		"X-Total-Count":                 strconv.Itoa(totalCount),
+		"X-Total-Pages":                 strconv.Itoa(pageCount),
+		"Access-Control-Expose-Headers": "X-Total-Count,X-Total-Pages",
+		"Content-Type":                  "application/json; charset=utf-8",
+	}
+}
+
+func errorBody(statusCode int, err error) string {
+	message := err.Error()
+	if statusCode >= http.StatusInternalServerError {
+		message = http.StatusText(statusCode)
+	}
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	enc.SetEscapeHTML(false)
+	enc.Encode(map[string]string{"message": message})
+	return string(bytes.TrimSpace(body.Bytes()))
+}
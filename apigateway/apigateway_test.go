@@ -0,0 +1,188 @@
+package apigateway_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/apigateway"
+)
+
+type numberFetcher struct {
+	numbers []int
+}
+
+func (nf *numberFetcher) Count(cond interface{}) (int, error) {
+	return len(nf.numbers), nil
+}
+
+func (nf *numberFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	toIndex := input.Offset + input.Limit
+	if toIndex > len(nf.numbers) {
+		toIndex = len(nf.numbers)
+	}
+	for _, n := range nf.numbers[input.Offset:toIndex] {
+		*result = append(*result, n)
+	}
+	return nil
+}
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *apigateway.ProxyRequest
+		want    *pagination.Query
+		wantErr bool
+	}{
+		{"query string parameters", &apigateway.ProxyRequest{
+			HTTPMethod:            "GET",
+			QueryStringParameters: map[string]string{"limit": "5", "page": "2", "sort": "-price"},
+		}, &pagination.Query{Limit: 5, Page: 2, Sort: []*pagination.Order{
+			&pagination.Order{Direction: pagination.DirectionDesc, ColumnName: "price"},
+		}, Enabled: true}, false},
+		{"multi-value query string parameters", &apigateway.ProxyRequest{
+			HTTPMethod:                      "GET",
+			QueryStringParameters:           map[string]string{"limit": "5", "sort": "+name"},
+			MultiValueQueryStringParameters: map[string][]string{"limit": {"5"}, "sort": {"-price", "+name"}},
+		}, &pagination.Query{Limit: 5, Page: 1, Sort: []*pagination.Order{
+			&pagination.Order{Direction: pagination.DirectionDesc, ColumnName: "price"},
+			&pagination.Order{Direction: pagination.DirectionAsc, ColumnName: "name"},
+		}, Enabled: true}, false},
+		{"JSON body", &apigateway.ProxyRequest{
+			HTTPMethod: "POST",
+			Headers:    map[string]string{"content-type": "application/json"},
+			Body:       `{"limit": 20, "offset": 40}`,
+		}, &pagination.Query{Limit: 20, Page: 1, Sort: []*pagination.Order{}, Enabled: true, Offset: 40}, false},
+		{"base64 encoded JSON body", &apigateway.ProxyRequest{
+			HTTPMethod:        "POST",
+			MultiValueHeaders: map[string][]string{"Content-Type": {"application/json"}},
+			Body:              base64.StdEncoding.EncodeToString([]byte(`{"pagination": false}`)),
+			IsBase64Encoded:   true,
+		}, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: false}, false},
+		{"invalid base64 body", &apigateway.ProxyRequest{
+			HTTPMethod:      "POST",
+			Body:            "!",
+			IsBase64Encoded: true,
+		}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := apigateway.ParseRequest(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRequest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseV2Request(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *apigateway.V2HTTPRequest
+		want    *pagination.Query
+		wantErr bool
+	}{
+		{"raw query string", &apigateway.V2HTTPRequest{
+			RawQueryString:        "limit=5&sort=-price&sort=%2Bname",
+			QueryStringParameters: map[string]string{"limit": "5", "sort": "-price,+name"},
+			RequestContext:        apigateway.V2HTTPRequestContext{HTTP: apigateway.V2HTTPRequestContextHTTP{Method: "GET"}},
+		}, &pagination.Query{Limit: 5, Page: 1, Sort: []*pagination.Order{
+			&pagination.Order{Direction: pagination.DirectionDesc, ColumnName: "price"},
+			&pagination.Order{Direction: pagination.DirectionAsc, ColumnName: "name"},
+		}, Enabled: true}, false},
+		{"query string parameters only", &apigateway.V2HTTPRequest{
+			QueryStringParameters: map[string]string{"page": "3"},
+		}, &pagination.Query{Limit: 10, Page: 3, Sort: []*pagination.Order{}, Enabled: true}, false},
+		{"JSON body", &apigateway.V2HTTPRequest{
+			Headers:        map[string]string{"content-type": "application/json"},
+			RequestContext: apigateway.V2HTTPRequestContext{HTTP: apigateway.V2HTTPRequestContextHTTP{Method: "POST"}},
+			Body:           `{"page": 4}`,
+		}, &pagination.Query{Limit: 10, Page: 4, Sort: []*pagination.Order{}, Enabled: true}, false},
+		{"invalid raw query string", &apigateway.V2HTTPRequest{
+			RawQueryString: "limit=%zz",
+		}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := apigateway.ParseV2Request(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseV2Request() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseV2Request() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResponse(t *testing.T) {
+	fetcher := &numberFetcher{numbers: []int{1, 2, 3, 4, 5}}
+	totalCount, pageCount, res, err := pagination.Fetch(fetcher, &pagination.Setting{Limit: 2, Page: 1})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	wantHeaders := map[string]string{
+		"X-Total-Count":                 "5",
+		"X-Total-Pages":                 "3",
+		"Access-Control-Expose-Headers": "X-Total-Count,X-Total-Pages",
+		"Content-Type":                  "application/json; charset=utf-8",
+	}
+
+	got, err := apigateway.NewResponse(totalCount, pageCount, res)
+	if err != nil {
+		t.Fatalf("NewResponse() error = %v", err)
+	}
+	if got.StatusCode != 200 {
+		t.Errorf("NewResponse() StatusCode = %v, want 200", got.StatusCode)
+	}
+	if !reflect.DeepEqual(got.Headers, wantHeaders) {
+		t.Errorf("NewResponse() Headers = %v, want %v", got.Headers, wantHeaders)
+	}
+	var body struct {
+		Pages map[string][]int `json:"pages"`
+	}
+	if err := json.Unmarshal([]byte(got.Body), &body); err != nil {
+		t.Fatalf("NewResponse() Body = %v, error = %v", got.Body, err)
+	}
+	if !reflect.DeepEqual(body.Pages["active"], []int{1, 2}) {
+		t.Errorf("NewResponse() Body pages.active = %v, want %v", body.Pages["active"], []int{1, 2})
+	}
+
+	gotV2, err := apigateway.NewV2Response(totalCount, pageCount, res)
+	if err != nil {
+		t.Fatalf("NewV2Response() error = %v", err)
+	}
+	if gotV2.StatusCode != 200 || gotV2.Body != got.Body {
+		t.Errorf("NewV2Response() = %+v, want the same body as %+v", gotV2, got)
+	}
+	if !reflect.DeepEqual(gotV2.Headers, wantHeaders) {
+		t.Errorf("NewV2Response() Headers = %v, want %v", gotV2.Headers, wantHeaders)
+	}
+}
+
+func TestNewErrorResponse(t *testing.T) {
+	got := apigateway.NewErrorResponse(400, errors.New("page must be >= 1"))
+	if got.StatusCode != 400 {
+		t.Errorf("NewErrorResponse() StatusCode = %v, want 400", got.StatusCode)
+	}
+	if want := `{"message":"page must be >= 1"}`; got.Body != want {
+		t.Errorf("NewErrorResponse() Body = %v, want %v", got.Body, want)
+	}
+
+	gotV2 := apigateway.NewV2ErrorResponse(500, errors.New("db is down"))
+	if gotV2.StatusCode != 500 {
+		t.Errorf("NewV2ErrorResponse() StatusCode = %v, want 500", gotV2.StatusCode)
+	}
+	if want := `{"message":"Internal Server Error"}`; gotV2.Body != want {
+		t.Errorf("NewV2ErrorResponse() Body = %v, want %v", gotV2.Body, want)
+	}
+}
@@ -0,0 +1,45 @@
+package pagination
+
+import "context"
+
+type queryKey struct{}
+
+// WithQuery returns a copy of ctx which carries the pagination parameters.
+func WithQuery(ctx context.Context, q *Query) context.Context {
+	return context.WithValue(ctx, queryKey{}, q)
+}
+
+// QueryFrom returns the pagination parameters carried by ctx.
+func QueryFrom(ctx context.Context) (*Query, bool) {
+	q, ok := ctx.Value(queryKey{}).(*Query)
+	return q, ok
+}
+
+// SettingFrom returns the setting of Fetch for the pagination parameters carried by ctx.
+// The default parameters of DefaultParser are used if ctx carries none.
+func SettingFrom(ctx context.Context, cond interface{}) *Setting {
+	q, ok := QueryFrom(ctx)
+	if !ok {
+		q = DefaultParser.defaultQuery()
+	}
+	return q.Setting(cond)
+}
+
+// FetchContext is Fetch with the context, which is passed to TxPageFetcher.Begin.
+// If setting is nil, it is taken from the pagination parameters carried by ctx.
+func FetchContext(ctx context.Context, fetcher PageFetcher, setting *Setting) (totalCount, pageCount int, res *PagingResponse, err error) {
+	if setting == nil {
+		setting = SettingFrom(ctx, nil)
+	}
+	pager, err := newPager(fetcher, setting)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	pager.ctx = ctx
+	res, err = pager.GetPages()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	return pager.totalCount, pager.GetPageCount(), res, nil
+}
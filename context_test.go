@@ -0,0 +1,76 @@
+package pagination_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+func TestQueryFrom(t *testing.T) {
+	q := pagination.ParseQuery("/fruits?limit=3&page=2")
+	ctx := pagination.WithQuery(context.Background(), q)
+
+	if got, ok := pagination.QueryFrom(ctx); !ok || got != q {
+		t.Errorf("QueryFrom() = %v, %v, want %v", got, ok, q)
+	}
+	if got, ok := pagination.QueryFrom(context.Background()); ok || got != nil {
+		t.Errorf("QueryFrom() = %v, %v, want nil", got, ok)
+	}
+}
+
+func TestSettingFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *pagination.Setting
+	}{
+		{"query", pagination.WithQuery(context.Background(), pagination.ParseQuery("/fruits?limit=3&page=2&sort=-price")), &pagination.Setting{
+			Limit:  3,
+			Page:   2,
+			Cond:   "cond",
+			Orders: []*pagination.Order{{Direction: pagination.DirectionDesc, ColumnName: "price"}},
+		}},
+		{"default", context.Background(), &pagination.Setting{Limit: 10, Page: 1, Cond: "cond", Orders: []*pagination.Order{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pagination.SettingFrom(tt.ctx, "cond"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SettingFrom() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// contextFetcher records the context given to Begin.
+type contextFetcher struct {
+	snapshotFetcher
+	ctx context.Context
+}
+
+func (cf *contextFetcher) Begin(ctx context.Context) (pagination.PageFetcherTx, error) {
+	cf.ctx = ctx
+	return cf.snapshotFetcher.Begin(ctx)
+}
+
+func TestFetchContext(t *testing.T) {
+	ctx := pagination.WithQuery(context.Background(), pagination.ParseQuery("/fruits?limit=20&page=3"))
+	fetcher := &contextFetcher{}
+	totalCount, pageCount, res, err := pagination.FetchContext(ctx, fetcher, nil)
+	if err != nil {
+		t.Fatalf("FetchContext() error = %v", err)
+	}
+	if totalCount != 103 || pageCount != 6 || res.ActivePage != 3 {
+		t.Errorf("FetchContext() = %v, %v, page %v, want 103, 6, page 3", totalCount, pageCount, res.ActivePage)
+	}
+	if fetcher.ctx != ctx {
+		t.Errorf("FetchContext() must begin the snapshot with the context")
+	}
+
+	// the setting takes precedence over the context
+	_, _, res, err = pagination.FetchContext(ctx, fetcher, &pagination.Setting{Limit: 20, Page: 1})
+	if err != nil || res.ActivePage != 1 {
+		t.Errorf("FetchContext() = page %v, %v, want page 1", res.ActivePage, err)
+	}
+}
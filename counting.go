@@ -0,0 +1,62 @@
+package pagination
+
+import (
+	"math"
+	"time"
+)
+
+// CountingPageFetcher is a PageFetcher which can count all records in the same query as a page,
+// e.g. with COUNT(*) OVER() of SQL window functions.
+// Pager fetches the chunk of the active and side pages with FetchPageWithCount instead of calling Count.
+// Hooks and metrics of the count are reported from this call.
+type CountingPageFetcher interface {
+	PageFetcher
+	// FetchPageWithCount fetches records like FetchPage, and returns the count of all records of the condition.
+	// The count must be returned even if no record is in the range.
+	FetchPageWithCount(cond interface{}, input *PageFetchInput, result *PageFetchResult) (int, error)
+}
+
+// fetchWithCount fetches the chunk of the active and side pages, and sets the total count.
+// The total count is unknown before the fetch, so the chunk is fetched as if the last page is far.
+// It returns the offset of the chunk, which must be fetched again when it turns out to be near the last page.
+// The count is reported to the count hooks and metrics with the elapsed time of the whole call.
+func (p *Pager) fetchWithCount(fetcher CountingPageFetcher) (chunk PageFetchResult, offset int, err error) {
+	h := p.hooks
+	if h == nil {
+		h = &Hooks{}
+	}
+
+	p.totalCount = math.MaxInt32
+	limit, offset := p.GetActiveAndSidesLimit()
+
+	var count int
+	chunk = make(PageFetchResult, 0, limit)
+	input := &PageFetchInput{
+		Limit:  limit,
+		Offset: offset,
+		Orders: p.Orders,
+	}
+	if h.BeforeCount != nil {
+		h.BeforeCount(p.ctx, p.Condition)
+	}
+	start := time.Now()
+	err = p.observeFetch(StageActive, input, func() (int, error) {
+		var err error
+		count, err = fetcher.FetchPageWithCount(p.Condition, input, &chunk)
+		return len(chunk), err
+	})
+	elapsed := time.Since(start)
+	p.totalCount = count
+	if err != nil {
+		// the error is reported once, by the active stage
+		return nil, 0, err
+	}
+
+	if p.metrics != nil {
+		p.metrics.ObserveCount(elapsed, nil)
+	}
+	if h.AfterCount != nil {
+		h.AfterCount(p.ctx, p.Condition, count, elapsed)
+	}
+	return chunk, offset, nil
+}
@@ -0,0 +1,106 @@
+package pagination_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// countingLargeDataFetcher counts records in FetchPageWithCount.
+type countingLargeDataFetcher struct {
+	LargeDataFetcher
+	calls []string
+}
+
+func (cf *countingLargeDataFetcher) Count(cond interface{}) (int, error) {
+	cf.calls = append(cf.calls, "count")
+	return cf.LargeDataFetcher.Count(cond)
+}
+
+func (cf *countingLargeDataFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	cf.calls = append(cf.calls, fmt.Sprintf("fetch %d+%d", input.Offset, input.Limit))
+	return cf.LargeDataFetcher.FetchPage(cond, input, result)
+}
+
+func (cf *countingLargeDataFetcher) FetchPageWithCount(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) (int, error) {
+	cf.calls = append(cf.calls, fmt.Sprintf("fetch with count %d+%d", input.Offset, input.Limit))
+	if input.Offset < len(dummyLargeList) {
+		if err := cf.LargeDataFetcher.FetchPage(cond, input, result); err != nil {
+			return 0, err
+		}
+	}
+	return len(dummyLargeList), nil
+}
+
+func TestFetch_CountingPageFetcher(t *testing.T) {
+	tests := []struct {
+		name      string
+		setting   *pagination.Setting
+		wantCalls []string
+	}{
+		{"first page", &pagination.Setting{Limit: 10, Page: 1}, []string{"fetch with count 0+50", "fetch 100+10"}},
+		{"middle page", &pagination.Setting{Limit: 10, Page: 5}, []string{"fetch with count 20+50", "fetch 0+10", "fetch 100+10"}},
+		{"near the last page", &pagination.Setting{Limit: 10, Page: 10}, []string{"fetch with count 70+50", "fetch 60+50", "fetch 0+10"}},
+		{"unaligned offset", &pagination.Setting{Limit: 10, Offset: 35}, []string{"fetch with count 15+50", "fetch 0+5", "fetch 95+10"}},
+		{"all in one page", &pagination.Setting{Limit: 200, Page: 1}, []string{"fetch with count 0+1000"}},
+		{"clamped", &pagination.Setting{Limit: 10, Page: 20, OutOfRange: pagination.OutOfRangeClamp}, []string{"fetch with count 170+50", "fetch 60+50", "fetch 0+10"}},
+		{"empty", &pagination.Setting{Limit: 10, Page: 20, OutOfRange: pagination.OutOfRangeEmpty}, []string{"fetch with count 170+50", "fetch 0+10", "fetch 100+10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := &countingLargeDataFetcher{}
+			totalCount, pageCount, res, err := pagination.Fetch(fetcher, tt.setting)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if !reflect.DeepEqual(fetcher.calls, tt.wantCalls) {
+				t.Errorf("Fetch() calls = %q, want %q", fetcher.calls, tt.wantCalls)
+			}
+
+			wantTotalCount, wantPageCount, want, err := pagination.Fetch(newLargeDataFetcher(), tt.setting)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if totalCount != wantTotalCount || pageCount != wantPageCount {
+				t.Errorf("Fetch() = %v, %v, want %v, %v", totalCount, pageCount, wantTotalCount, wantPageCount)
+			}
+			if !reflect.DeepEqual(res, want) {
+				t.Errorf("Fetch() = %v, want %v", res, want)
+			}
+		})
+	}
+}
+
+func TestFetch_CountingPageFetcherOutOfRange(t *testing.T) {
+	fetcher := &countingLargeDataFetcher{}
+	_, _, _, err := pagination.Fetch(fetcher, &pagination.Setting{Limit: 10, Page: 20})
+	if _, ok := err.(*pagination.OutOfRangeError); !ok {
+		t.Errorf("Fetch() error = %v, want *OutOfRangeError", err)
+	}
+	if want := []string{"fetch with count 170+50"}; !reflect.DeepEqual(fetcher.calls, want) {
+		t.Errorf("Fetch() calls = %q, want %q", fetcher.calls, want)
+	}
+}
+
+func TestFetch_CountingPageFetcherHooks(t *testing.T) {
+	calls := []string{}
+	_, _, _, err := pagination.Fetch(&countingLargeDataFetcher{}, &pagination.Setting{Limit: 10, Page: 5, Hooks: recordingHooks(&calls)})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	want := []string{
+		"before count",
+		"before active 50/20",
+		"after active 50/20 50 rows",
+		"after count 103",
+		"before first 10/0",
+		"after first 10/0 10 rows",
+		"before last 10/100",
+		"after last 10/100 3 rows",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Fetch() hooks = %q, want %q", calls, want)
+	}
+}
@@ -0,0 +1,508 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FetcherMiddleware decorates a PageFetcher.
+type FetcherMiddleware func(PageFetcher) PageFetcher
+
+// DecorateFetcher wraps the fetcher with the middlewares. The first middleware is the outermost.
+// Pager and Scan find optional interfaces like TxPageFetcher of the fetcher through the middlewares of this package,
+// whose calls are decorated too, and so are the calls to the fetcher bound to the snapshot of TxPageFetcher.
+func DecorateFetcher(fetcher PageFetcher, middlewares ...FetcherMiddleware) PageFetcher {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		fetcher = middlewares[i](fetcher)
+	}
+	return fetcher
+}
+
+// Clock tells the time and waits for durations. Inject a fake one for deterministic tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// DecoratorOption configures WithRetry, WithTimeout and WithCircuitBreaker.
+type DecoratorOption func(*decoratorOptions)
+
+type decoratorOptions struct {
+	clock     Clock
+	retryable func(err error) bool
+}
+
+// UseClock replaces the real clock.
+func UseClock(clock Clock) DecoratorOption {
+	return func(o *decoratorOptions) {
+		o.clock = clock
+	}
+}
+
+// RetryIf classifies transient errors. WithRetry retries only them, and WithCircuitBreaker counts only them as failures.
+// All errors are transient by default, except ErrCircuitOpen, which is never retried.
+func RetryIf(retryable func(err error) bool) DecoratorOption {
+	return func(o *decoratorOptions) {
+		o.retryable = retryable
+	}
+}
+
+func newDecoratorOptions(opts []DecoratorOption) *decoratorOptions {
+	o := &decoratorOptions{
+		clock:     realClock{},
+		retryable: func(err error) bool { return true },
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// guard runs calls to a fetcher, e.g. retries them.
+type guard interface {
+	do(call func() error) error
+}
+
+// guardedFetcher is a PageFetcher of WithRetry, WithTimeout and WithCircuitBreaker, whose calls to the fetcher go through the guard.
+// Records are fetched into new results, so a failed or abandoned call leaves the results of the caller untouched.
+type guardedFetcher struct {
+	fetcher PageFetcher
+	guard   guard
+}
+
+func (f *guardedFetcher) guarded() *guardedFetcher {
+	return f
+}
+
+func (f *guardedFetcher) Count(cond interface{}) (int, error) {
+	var count int
+	err := f.guard.do(func() error {
+		var err error
+		count, err = f.fetcher.Count(cond)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (f *guardedFetcher) FetchPage(cond interface{}, input *PageFetchInput, result *PageFetchResult) error {
+	var records PageFetchResult
+	err := f.guard.do(func() error {
+		in := *input
+		records = nil
+		return f.fetcher.FetchPage(cond, &in, &records)
+	})
+	if err != nil {
+		return err
+	}
+	*result = append(*result, records...)
+	return nil
+}
+
+// begin begins the snapshot of the fetcher, and guards the calls to the fetcher bound to it.
+// A snapshot begun by an abandoned call is rolled back.
+func (f *guardedFetcher) begin(ctx context.Context, fetcher TxPageFetcher) (PageFetcherTx, error) {
+	var mu sync.Mutex
+	var tx PageFetcherTx
+	abandoned := false
+	err := f.guard.do(func() error {
+		begun, err := fetcher.Begin(ctx)
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil && abandoned {
+			begun.Rollback()
+			return nil
+		}
+		tx = begun
+		return err
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if err != nil {
+		abandoned = true
+		if tx != nil {
+			tx.Rollback()
+		}
+		return nil, err
+	}
+	return &guardedTx{guardedFetcher: &guardedFetcher{fetcher: tx, guard: f.guard}, tx: tx}, nil
+}
+
+func (f *guardedFetcher) countLowerBound(fetcher LowerBoundPageFetcher, cond interface{}) (int, bool, error) {
+	var count int
+	var lowerBound bool
+	err := f.guard.do(func() error {
+		var err error
+		count, lowerBound, err = fetcher.CountLowerBound(cond)
+		return err
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return count, lowerBound, nil
+}
+
+func (f *guardedFetcher) fetchPageWithCount(fetcher CountingPageFetcher, cond interface{}, input *PageFetchInput, result *PageFetchResult) (int, error) {
+	var count int
+	var records PageFetchResult
+	err := f.guard.do(func() error {
+		in := *input
+		records = nil
+		var err error
+		count, err = fetcher.FetchPageWithCount(cond, &in, &records)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	*result = append(*result, records...)
+	return count, nil
+}
+
+func (f *guardedFetcher) fetchRanges(fetcher MultiRangeFetcher, cond interface{}, inputs []*PageFetchInput, results []*PageFetchResult) error {
+	var records []PageFetchResult
+	err := f.guard.do(func() error {
+		ins := make([]*PageFetchInput, len(inputs))
+		outs := make([]*PageFetchResult, len(inputs))
+		records = make([]PageFetchResult, len(inputs))
+		for i, input := range inputs {
+			in := *input
+			ins[i] = &in
+			outs[i] = &records[i]
+		}
+		return fetcher.FetchRanges(cond, ins, outs)
+	})
+	if err != nil {
+		return err
+	}
+	for i, result := range results {
+		*result = append(*result, records[i]...)
+	}
+	return nil
+}
+
+func (f *guardedFetcher) fetchKeyset(fetcher KeysetFetcher, cond interface{}, input *KeysetInput, result *PageFetchResult) error {
+	var records PageFetchResult
+	err := f.guard.do(func() error {
+		in := *input
+		records = nil
+		return fetcher.FetchKeyset(cond, &in, &records)
+	})
+	if err != nil {
+		return err
+	}
+	*result = append(*result, records...)
+	return nil
+}
+
+// guardedTx is the fetcher bound to a snapshot begun through guardedFetcher.
+// Commit and Rollback are not guarded, since retrying them is not safe.
+type guardedTx struct {
+	*guardedFetcher
+	tx PageFetcherTx
+}
+
+func (f *guardedTx) Commit() error   { return f.tx.Commit() }
+func (f *guardedTx) Rollback() error { return f.tx.Rollback() }
+
+// decorated is a fetcher of the middlewares of this package, which forwards optional interfaces of the fetcher inside.
+type decorated interface {
+	guarded() *guardedFetcher
+}
+
+type guardedTxFetcher struct {
+	*guardedFetcher
+	inner TxPageFetcher
+}
+
+func (f *guardedTxFetcher) Begin(ctx context.Context) (PageFetcherTx, error) {
+	return f.begin(ctx, f.inner)
+}
+
+// asTxPageFetcher returns the fetcher as TxPageFetcher, looking through the middlewares.
+func asTxPageFetcher(fetcher PageFetcher) (TxPageFetcher, bool) {
+	d, ok := fetcher.(decorated)
+	if !ok {
+		f, ok := fetcher.(TxPageFetcher)
+		return f, ok
+	}
+	inner, ok := asTxPageFetcher(d.guarded().fetcher)
+	if !ok {
+		return nil, false
+	}
+	return &guardedTxFetcher{d.guarded(), inner}, true
+}
+
+type guardedLowerBoundFetcher struct {
+	*guardedFetcher
+	inner LowerBoundPageFetcher
+}
+
+func (f *guardedLowerBoundFetcher) CountLowerBound(cond interface{}) (int, bool, error) {
+	return f.countLowerBound(f.inner, cond)
+}
+
+// asLowerBoundPageFetcher returns the fetcher as LowerBoundPageFetcher, looking through the middlewares.
+func asLowerBoundPageFetcher(fetcher PageFetcher) (LowerBoundPageFetcher, bool) {
+	d, ok := fetcher.(decorated)
+	if !ok {
+		f, ok := fetcher.(LowerBoundPageFetcher)
+		return f, ok
+	}
+	inner, ok := asLowerBoundPageFetcher(d.guarded().fetcher)
+	if !ok {
+		return nil, false
+	}
+	return &guardedLowerBoundFetcher{d.guarded(), inner}, true
+}
+
+type guardedCountingFetcher struct {
+	*guardedFetcher
+	inner CountingPageFetcher
+}
+
+func (f *guardedCountingFetcher) FetchPageWithCount(cond interface{}, input *PageFetchInput, result *PageFetchResult) (int, error) {
+	return f.fetchPageWithCount(f.inner, cond, input, result)
+}
+
+// asCountingPageFetcher returns the fetcher as CountingPageFetcher, looking through the middlewares.
+func asCountingPageFetcher(fetcher PageFetcher) (CountingPageFetcher, bool) {
+	d, ok := fetcher.(decorated)
+	if !ok {
+		f, ok := fetcher.(CountingPageFetcher)
+		return f, ok
+	}
+	inner, ok := asCountingPageFetcher(d.guarded().fetcher)
+	if !ok {
+		return nil, false
+	}
+	return &guardedCountingFetcher{d.guarded(), inner}, true
+}
+
+type guardedMultiRangeFetcher struct {
+	*guardedFetcher
+	inner MultiRangeFetcher
+}
+
+func (f *guardedMultiRangeFetcher) FetchRanges(cond interface{}, inputs []*PageFetchInput, results []*PageFetchResult) error {
+	return f.fetchRanges(f.inner, cond, inputs, results)
+}
+
+// asMultiRangeFetcher returns the fetcher as MultiRangeFetcher, looking through the middlewares.
+func asMultiRangeFetcher(fetcher PageFetcher) (MultiRangeFetcher, bool) {
+	d, ok := fetcher.(decorated)
+	if !ok {
+		f, ok := fetcher.(MultiRangeFetcher)
+		return f, ok
+	}
+	inner, ok := asMultiRangeFetcher(d.guarded().fetcher)
+	if !ok {
+		return nil, false
+	}
+	return &guardedMultiRangeFetcher{d.guarded(), inner}, true
+}
+
+type guardedKeysetFetcher struct {
+	*guardedFetcher
+	inner KeysetFetcher
+}
+
+// Cursor is not guarded, since it makes the cursor of a fetched record without calling the database.
+func (f *guardedKeysetFetcher) Cursor(record interface{}) (string, error) {
+	return f.inner.Cursor(record)
+}
+
+func (f *guardedKeysetFetcher) FetchKeyset(cond interface{}, input *KeysetInput, result *PageFetchResult) error {
+	return f.fetchKeyset(f.inner, cond, input, result)
+}
+
+// AsKeysetFetcher returns the fetcher as KeysetFetcher, looking through the middlewares of this package.
+// Use it instead of a type assertion, which fails for a decorated fetcher.
+func AsKeysetFetcher(fetcher PageFetcher) (KeysetFetcher, bool) {
+	d, ok := fetcher.(decorated)
+	if !ok {
+		f, ok := fetcher.(KeysetFetcher)
+		return f, ok
+	}
+	inner, ok := AsKeysetFetcher(d.guarded().fetcher)
+	if !ok {
+		return nil, false
+	}
+	return &guardedKeysetFetcher{d.guarded(), inner}, true
+}
+
+// Backoff returns the delay before the retry (1〜), and false to give up.
+type Backoff func(retry int) (time.Duration, bool)
+
+// ConstantBackoff retries maxRetries times with the same delay.
+func ConstantBackoff(delay time.Duration, maxRetries int) Backoff {
+	return func(retry int) (time.Duration, bool) {
+		return delay, retry <= maxRetries
+	}
+}
+
+// ExponentialBackoff retries maxRetries times, doubling the delay from base up to max.
+func ExponentialBackoff(base, max time.Duration, maxRetries int) Backoff {
+	return func(retry int) (time.Duration, bool) {
+		delay := base
+		for i := 1; i < retry && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		return delay, retry <= maxRetries
+	}
+}
+
+// WithRetry retries calls to the fetcher on transient errors, waiting for the delays of backoff.
+// The last error is returned when backoff gives up.
+func WithRetry(backoff Backoff, opts ...DecoratorOption) FetcherMiddleware {
+	o := newDecoratorOptions(opts)
+	return func(fetcher PageFetcher) PageFetcher {
+		return &guardedFetcher{fetcher: fetcher, guard: &retryGuard{backoff: backoff, opts: o}}
+	}
+}
+
+type retryGuard struct {
+	backoff Backoff
+	opts    *decoratorOptions
+}
+
+func (g *retryGuard) do(call func() error) error {
+	for retry := 1; ; retry++ {
+		err := call()
+		if err == nil || errors.Is(err, ErrCircuitOpen) || !g.opts.retryable(err) {
+			return err
+		}
+		delay, ok := g.backoff(retry)
+		if !ok {
+			return err
+		}
+		<-g.opts.clock.After(delay)
+	}
+}
+
+// WithTimeout fails calls to the fetcher with ErrTimeout when they take longer than d.
+// PageFetcher has no context, so the call is abandoned in background rather than canceled.
+// The abandoned call keeps running, so the fetcher may be called again while it runs, e.g. by WithRetry outside of WithTimeout.
+// The fetcher must be safe for concurrent calls then.
+func WithTimeout(d time.Duration, opts ...DecoratorOption) FetcherMiddleware {
+	o := newDecoratorOptions(opts)
+	return func(fetcher PageFetcher) PageFetcher {
+		return &guardedFetcher{fetcher: fetcher, guard: &timeoutGuard{timeout: d, opts: o}}
+	}
+}
+
+type timeoutGuard struct {
+	timeout time.Duration
+	opts    *decoratorOptions
+}
+
+// do runs call in background, and waits for it until the timeout.
+func (g *timeoutGuard) do(call func() error) error {
+	// buffered, so the abandoned call does not block forever
+	done := make(chan error, 1)
+	go func() {
+		done <- call()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-g.opts.clock.After(g.timeout):
+		return fmt.Errorf("%w after %v", ErrTimeout, g.timeout)
+	}
+}
+
+// CircuitBreakerConfig configures WithCircuitBreaker. Zero values are replaced with defaults.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the count of consecutive transient errors to open the circuit. 5 if 0.
+	FailureThreshold int
+	// OpenTimeout is how long the open circuit rejects calls before a trial call. 30 seconds if 0.
+	OpenTimeout time.Duration
+}
+
+// WithCircuitBreaker rejects calls to the fetcher with ErrCircuitOpen while the fetcher keeps failing.
+// After OpenTimeout a single trial call is let through, which closes the circuit on success and reopens it on a transient error.
+// The circuit is shared by all pagers of the decorated fetcher.
+func WithCircuitBreaker(cfg CircuitBreakerConfig, opts ...DecoratorOption) FetcherMiddleware {
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout == 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	o := newDecoratorOptions(opts)
+	return func(fetcher PageFetcher) PageFetcher {
+		return &guardedFetcher{fetcher: fetcher, guard: &breakerGuard{cfg: cfg, opts: o}}
+	}
+}
+
+type breakerGuard struct {
+	cfg  CircuitBreakerConfig
+	opts *decoratorOptions
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	open     bool
+	trial    bool
+}
+
+func (g *breakerGuard) do(call func() error) error {
+	if err := g.allow(); err != nil {
+		return err
+	}
+	err := call()
+	g.done(err)
+	return err
+}
+
+// allow rejects the call while the circuit is open, except a single trial after OpenTimeout.
+func (g *breakerGuard) allow() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.open {
+		return nil
+	}
+	if g.trial || g.opts.clock.Now().Sub(g.openedAt) < g.cfg.OpenTimeout {
+		return ErrCircuitOpen
+	}
+	g.trial = true
+	return nil
+}
+
+// done records the result of the call.
+func (g *breakerGuard) done(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err != nil && !g.opts.retryable(err) {
+		// permanent errors say nothing about the health of the fetcher
+		g.trial = false
+		return
+	}
+	if err == nil {
+		g.failures = 0
+		g.open = false
+		g.trial = false
+		return
+	}
+
+	g.failures++
+	if g.trial || g.failures >= g.cfg.FailureThreshold {
+		g.open = true
+		g.openedAt = g.opts.clock.Now()
+	}
+	g.trial = false
+}
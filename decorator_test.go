@@ -0,0 +1,354 @@
+package pagination_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/paginationtest"
+)
+
+// fakeClock is a clock of manual time, whose After fires immediately or never.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	never  bool
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	ch := make(chan time.Time, 1)
+	if !c.never {
+		c.now = c.now.Add(d)
+		ch <- c.now
+	}
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// flakyFetcher fails the first calls of Count and FetchPage with the errors.
+type flakyFetcher struct {
+	pagination.PageFetcher
+	errs []error
+}
+
+func (f *flakyFetcher) fail() error {
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *flakyFetcher) Count(cond interface{}) (int, error) {
+	if err := f.fail(); err != nil {
+		return 0, err
+	}
+	return f.PageFetcher.Count(cond)
+}
+
+func (f *flakyFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	if err := f.fail(); err != nil {
+		// partial records of the failed call must not leak
+		*result = append(*result, "partial")
+		return err
+	}
+	return f.PageFetcher.FetchPage(cond, input, result)
+}
+
+var (
+	errTransient = errors.New("connection reset")
+	errPermanent = errors.New("syntax error")
+)
+
+func isTransient(err error) bool {
+	return errors.Is(err, errTransient)
+}
+
+func TestWithRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		errs   []error
+		err    error
+		sleeps []time.Duration
+	}{
+		{"success", nil, nil, nil},
+		{"retried", []error{errTransient, errTransient}, nil, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}},
+		{"gave up", []error{errTransient, errTransient, errTransient, errTransient}, errTransient, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}},
+		{"permanent", []error{errPermanent}, errPermanent, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{}
+			backoff := func(retry int) (time.Duration, bool) {
+				return time.Duration(retry) * 10 * time.Millisecond, retry <= 3
+			}
+			fetcher := pagination.WithRetry(backoff, pagination.RetryIf(isTransient), pagination.UseClock(clock))(
+				&flakyFetcher{PageFetcher: paginationtest.NewFetcher(1, 2, 3), errs: tt.errs},
+			)
+
+			var result pagination.PageFetchResult
+			err := fetcher.FetchPage(nil, &pagination.PageFetchInput{Limit: 2}, &result)
+			if err != tt.err {
+				t.Fatalf("FetchPage() error = %v, want %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(clock.sleeps, tt.sleeps) {
+				t.Errorf("sleeps = %v, want %v", clock.sleeps, tt.sleeps)
+			}
+			want := pagination.PageFetchResult{1, 2}
+			if err != nil {
+				want = nil
+			}
+			if !reflect.DeepEqual(result, want) {
+				t.Errorf("result = %v, want %v", result, want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff pagination.Backoff
+		want    []time.Duration
+	}{
+		{"constant", pagination.ConstantBackoff(time.Second, 3), []time.Duration{time.Second, time.Second, time.Second}},
+		{"exponential", pagination.ExponentialBackoff(time.Second, 5*time.Second, 5), []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []time.Duration{}
+			for retry := 1; ; retry++ {
+				delay, ok := tt.backoff(retry)
+				if !ok {
+					break
+				}
+				got = append(got, delay)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("delays = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// blockingFetcher blocks Count until released.
+type blockingFetcher struct {
+	pagination.PageFetcher
+	release chan struct{}
+}
+
+func (f *blockingFetcher) Count(cond interface{}) (int, error) {
+	<-f.release
+	return f.PageFetcher.Count(cond)
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		inner := &blockingFetcher{PageFetcher: paginationtest.NewFetcher(1, 2, 3), release: make(chan struct{})}
+		defer close(inner.release)
+		fetcher := pagination.WithTimeout(time.Second, pagination.UseClock(&fakeClock{}))(inner)
+
+		_, err := fetcher.Count(nil)
+		if !errors.Is(err, pagination.ErrTimeout) {
+			t.Errorf("Count() error = %v, want %v", err, pagination.ErrTimeout)
+		}
+	})
+
+	t.Run("in time", func(t *testing.T) {
+		fetcher := pagination.WithTimeout(time.Second, pagination.UseClock(&fakeClock{never: true}))(paginationtest.NewFetcher(1, 2, 3))
+
+		count, err := fetcher.Count(nil)
+		if err != nil || count != 3 {
+			t.Errorf("Count() = %v, %v, want 3", count, err)
+		}
+		var result pagination.PageFetchResult
+		if err := fetcher.FetchPage(nil, &pagination.PageFetchInput{Limit: 2, Offset: 1}, &result); err != nil {
+			t.Fatal(err)
+		}
+		if want := (pagination.PageFetchResult{2, 3}); !reflect.DeepEqual(result, want) {
+			t.Errorf("result = %v, want %v", result, want)
+		}
+	})
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	inner := &flakyFetcher{PageFetcher: paginationtest.NewFetcher(1, 2, 3)}
+	fetcher := pagination.WithCircuitBreaker(
+		pagination.CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute},
+		pagination.RetryIf(isTransient), pagination.UseClock(clock),
+	)(inner)
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		errs    []error
+		want    error
+	}{
+		{"closed", 0, nil, nil},
+		{"permanent error is not counted", 0, []error{errPermanent}, errPermanent},
+		{"first failure", 0, []error{errTransient}, errTransient},
+		{"permanent error keeps the count", 0, []error{errPermanent}, errPermanent},
+		{"second failure opens", 0, []error{errTransient}, errTransient},
+		{"open", 0, nil, pagination.ErrCircuitOpen},
+		{"still open", 30 * time.Second, nil, pagination.ErrCircuitOpen},
+		{"failed trial reopens", 30 * time.Second, []error{errTransient}, errTransient},
+		{"reopened", 30 * time.Second, nil, pagination.ErrCircuitOpen},
+		{"trial closes", 30 * time.Second, nil, nil},
+		{"closed again", 0, []error{errTransient}, errTransient},
+		{"count was reset", 0, nil, nil},
+	}
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		inner.errs = step.errs
+		if _, err := fetcher.Count(nil); err != step.want {
+			t.Errorf("%s: Count() error = %v, want %v", step.name, err, step.want)
+		}
+	}
+}
+
+func TestDecorateFetcher(t *testing.T) {
+	clock := &fakeClock{}
+	inner := &flakyFetcher{PageFetcher: paginationtest.NewFetcher(1, 2, 3, 4, 5), errs: []error{errTransient, errTransient}}
+	fetcher := pagination.DecorateFetcher(inner,
+		pagination.WithRetry(pagination.ConstantBackoff(time.Second, 3), pagination.UseClock(clock)),
+		pagination.WithCircuitBreaker(pagination.CircuitBreakerConfig{FailureThreshold: 5}, pagination.UseClock(clock)),
+	)
+
+	totalCount, _, res, err := pagination.Fetch(fetcher, &pagination.Setting{Limit: 2, Page: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalCount != 5 || !reflect.DeepEqual(res.Pages["active"], pagination.PageFetchResult{1, 2}) {
+		t.Errorf("Fetch() = %v, %v", totalCount, res.Pages["active"])
+	}
+	if len(clock.sleeps) != 2 {
+		t.Errorf("sleeps = %v, want 2 retries", clock.sleeps)
+	}
+}
+
+func TestDecorateFetcher_OptionalInterfaces(t *testing.T) {
+	decorate := func(fetcher pagination.PageFetcher) pagination.PageFetcher {
+		return pagination.DecorateFetcher(fetcher,
+			pagination.WithRetry(pagination.ConstantBackoff(time.Second, 3), pagination.UseClock(&fakeClock{})),
+			pagination.WithTimeout(time.Second, pagination.UseClock(&fakeClock{never: true})),
+			pagination.WithCircuitBreaker(pagination.CircuitBreakerConfig{}),
+		)
+	}
+
+	t.Run("TxPageFetcher", func(t *testing.T) {
+		inner := &snapshotFetcher{calls: []string{}}
+		if _, _, _, err := pagination.Fetch(decorate(inner), &pagination.Setting{Limit: 10, Page: 5}); err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"begin", "count", "fetch", "fetch", "fetch", "commit"}; !reflect.DeepEqual(inner.calls, want) {
+			t.Errorf("Fetch() calls = %q, want %q", inner.calls, want)
+		}
+	})
+
+	t.Run("CountingPageFetcher", func(t *testing.T) {
+		inner := &countingLargeDataFetcher{}
+		if _, _, _, err := pagination.Fetch(decorate(inner), &pagination.Setting{Limit: 10, Page: 1}); err != nil {
+			t.Fatal(err)
+		}
+		if len(inner.calls) == 0 || inner.calls[0] != "fetch with count 0+50" {
+			t.Errorf("Fetch() calls = %q, want fetch with count first", inner.calls)
+		}
+	})
+
+	t.Run("MultiRangeFetcher", func(t *testing.T) {
+		inner := &multiRangeLargeDataFetcher{}
+		_, _, res, err := pagination.Fetch(decorate(inner), &pagination.Setting{Limit: 10, Page: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"fetch ranges 20+50 0+10 100+10"}; !reflect.DeepEqual(inner.calls, want) {
+			t.Errorf("Fetch() calls = %q, want %q", inner.calls, want)
+		}
+		if len(res.Pages["first"]) != 10 || len(res.Pages["last"]) != 3 {
+			t.Errorf("Fetch() pages = %v", res.Pages)
+		}
+	})
+
+	t.Run("LowerBoundPageFetcher", func(t *testing.T) {
+		totalCount, _, res, err := pagination.Fetch(decorate(&lowerBoundLargeDataFetcher{}), &pagination.Setting{Limit: 10, Page: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if totalCount != 100 || !res.TotalLowerBound {
+			t.Errorf("Fetch() = %v, %v, want a lower bound of 100", totalCount, res.TotalLowerBound)
+		}
+	})
+}
+
+// slowBeginFetcher begins a snapshot when released, and reports its rollback.
+type slowBeginFetcher struct {
+	snapshotFetcher
+	release    chan struct{}
+	rolledBack chan struct{}
+}
+
+func (f *slowBeginFetcher) Begin(ctx context.Context) (pagination.PageFetcherTx, error) {
+	<-f.release
+	return &rollbackTx{snapshotFetcherTx{&f.snapshotFetcher}, f.rolledBack}, nil
+}
+
+type rollbackTx struct {
+	snapshotFetcherTx
+	rolledBack chan struct{}
+}
+
+func (tx *rollbackTx) Rollback() error {
+	close(tx.rolledBack)
+	return nil
+}
+
+func TestWithTimeout_Begin(t *testing.T) {
+	inner := &slowBeginFetcher{release: make(chan struct{}), rolledBack: make(chan struct{})}
+	fetcher := pagination.WithTimeout(time.Second, pagination.UseClock(&fakeClock{}))(inner)
+
+	_, _, _, err := pagination.Fetch(fetcher, &pagination.Setting{Limit: 10, Page: 1})
+	if !errors.Is(err, pagination.ErrTimeout) {
+		t.Fatalf("Fetch() error = %v, want %v", err, pagination.ErrTimeout)
+	}
+	// the snapshot begun after the timeout is not left open
+	close(inner.release)
+	select {
+	case <-inner.rolledBack:
+	case <-time.After(time.Second):
+		t.Error("the abandoned snapshot was not rolled back")
+	}
+}
+
+func TestWithRetry_CircuitOpen(t *testing.T) {
+	clock := &fakeClock{}
+	fetcher := pagination.WithRetry(pagination.ConstantBackoff(time.Second, 3), pagination.UseClock(clock))(
+		&flakyFetcher{PageFetcher: paginationtest.NewFetcher(1, 2, 3), errs: []error{pagination.ErrCircuitOpen}},
+	)
+	if _, err := fetcher.Count(nil); err != pagination.ErrCircuitOpen {
+		t.Errorf("Count() error = %v, want %v", err, pagination.ErrCircuitOpen)
+	}
+	if len(clock.sleeps) != 0 {
+		t.Errorf("sleeps = %v, want no retries", clock.sleeps)
+	}
+}
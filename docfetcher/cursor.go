@@ -0,0 +1,160 @@
+package docfetcher
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// cursorValue is a value of a cursor with its type, since plain JSON turns times into strings and integers into floats.
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v,omitempty"`
+}
+
+// basicTypes are the types of numbers restored from cursors by their names.
+var basicTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, v := range []interface{}{
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), float64(0),
+	} {
+		t := reflect.TypeOf(v)
+		basicTypes[t.Kind().String()] = t
+	}
+}
+
+// encodeCursor encodes the values of the keys with their types.
+func (f *Fetcher) encodeCursor(keys Document) (string, error) {
+	values := make(map[string]cursorValue, len(keys))
+	for k, v := range keys {
+		cv, err := f.encodeValue(v)
+		if err != nil {
+			return "", fmt.Errorf("docfetcher: field %q of cursor: %w", k, err)
+		}
+		values[k] = cv
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (f *Fetcher) encodeValue(v interface{}) (cursorValue, error) {
+	if v == nil {
+		return cursorValue{Type: "null"}, nil
+	}
+	// registered types come first, so named types of numbers and strings keep their names
+	if t, ok := f.cursorType(reflect.TypeOf(v).String()); ok && t == reflect.TypeOf(v) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return cursorValue{}, err
+		}
+		return cursorValue{Type: t.String(), Value: string(b)}, nil
+	}
+	switch x := v.(type) {
+	case string:
+		return cursorValue{Type: "string", Value: x}, nil
+	case bool:
+		return cursorValue{Type: "bool", Value: strconv.FormatBool(x)}, nil
+	case time.Time:
+		return cursorValue{Type: "time", Value: x.Format(time.RFC3339Nano)}, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cursorValue{Type: rv.Kind().String(), Value: strconv.FormatInt(rv.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cursorValue{Type: rv.Kind().String(), Value: strconv.FormatUint(rv.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return cursorValue{Type: rv.Kind().String(), Value: strconv.FormatFloat(rv.Float(), 'g', -1, 64)}, nil
+	}
+	return cursorValue{}, fmt.Errorf("type %T is not in CursorTypes", v)
+}
+
+// decodeCursor decodes the values of the keys into their types.
+func (f *Fetcher) decodeCursor(cursor string) (Document, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("docfetcher: %w: %v", pagination.ErrInvalidCursor, err)
+	}
+	values := map[string]cursorValue{}
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, fmt.Errorf("docfetcher: %w: %v", pagination.ErrInvalidCursor, err)
+	}
+	keys := make(Document, len(values))
+	for k, cv := range values {
+		v, err := f.decodeValue(cv)
+		if err != nil {
+			return nil, fmt.Errorf("docfetcher: %w: field %q: %v", pagination.ErrInvalidCursor, k, err)
+		}
+		keys[k] = v
+	}
+	return keys, nil
+}
+
+func (f *Fetcher) decodeValue(cv cursorValue) (interface{}, error) {
+	if t, ok := f.cursorType(cv.Type); ok {
+		p := reflect.New(t)
+		if err := json.Unmarshal([]byte(cv.Value), p.Interface()); err != nil {
+			return nil, err
+		}
+		return p.Elem().Interface(), nil
+	}
+	switch cv.Type {
+	case "null":
+		return nil, nil
+	case "string":
+		return cv.Value, nil
+	case "bool":
+		return strconv.ParseBool(cv.Value)
+	case "time":
+		return time.Parse(time.RFC3339Nano, cv.Value)
+	}
+
+	t, ok := basicTypes[cv.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", cv.Type)
+	}
+	rv := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(cv.Value, t.Bits())
+		if err != nil {
+			return nil, err
+		}
+		rv.SetFloat(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(cv.Value, 10, t.Bits())
+		if err != nil {
+			return nil, err
+		}
+		rv.SetUint(n)
+	default:
+		n, err := strconv.ParseInt(cv.Value, 10, t.Bits())
+		if err != nil {
+			return nil, err
+		}
+		rv.SetInt(n)
+	}
+	return rv.Interface(), nil
+}
+
+// cursorType returns the type of CursorTypes with the name.
+func (f *Fetcher) cursorType(name string) (reflect.Type, bool) {
+	for _, v := range f.CursorTypes {
+		if t := reflect.TypeOf(v); t != nil && t.String() == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
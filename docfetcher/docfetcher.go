@@ -0,0 +1,220 @@
+// Package docfetcher provides pagination.PageFetcher of document databases like MongoDB.
+// The database is accessed through the small Collection interface,
+// and MemoryCollection is an in-memory stand-in of it.
+package docfetcher
+
+import (
+	"context"
+	"fmt"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// Document is a document of the database, e.g. a filter document or a record.
+type Document map[string]interface{}
+
+// SortField is a field of a sort document. Order is 1 for ascending, and -1 for descending.
+type SortField struct {
+	Key   string
+	Order int
+}
+
+// FindOptions are options of Collection.Find.
+type FindOptions struct {
+	Sort  []SortField
+	Skip  int
+	Limit int
+}
+
+// Collection is a collection of the document database.
+type Collection interface {
+	CountDocuments(ctx context.Context, filter Document) (int, error)
+	Find(ctx context.Context, filter Document, opts *FindOptions) ([]Document, error)
+}
+
+// Fetcher is pagination.PageFetcher of a Collection.
+// It also implements pagination.KeysetFetcher, whose cursors hold the values of Fields and KeyField with their types,
+// so values like time.Time and int64 are compared as they are stored.
+type Fetcher struct {
+	Collection Collection
+	// Filter maps the condition to the filter document. All documents are fetched if nil.
+	Filter func(cond interface{}) Document
+	// Fields maps column names of pagination.Order to fields of documents.
+	// Orders of other columns are rejected.
+	Fields map[string]string
+	// KeyField is the unique field, which breaks ties of sorts and keyset cursors. "_id" is used if empty.
+	KeyField string
+	// Decode decodes a document into a record. The document itself is the record if nil.
+	Decode func(doc Document) (interface{}, error)
+	// Encode returns the document of a record for cursors. It is required if Decode is set.
+	Encode func(record interface{}) (Document, error)
+	// CursorTypes are zero values of types of cursor values other than numbers, strings, booleans and time.Time,
+	// e.g. primitive.ObjectID{}. They are encoded as JSON and decoded into the type of the same name.
+	// Cursor fails for values of other types.
+	CursorTypes []interface{}
+}
+
+// Count counts documents of the condition.
+func (f *Fetcher) Count(cond interface{}) (int, error) {
+	return f.Collection.CountDocuments(context.Background(), f.filter(cond))
+}
+
+// FetchPage fetches documents of the condition in the range of input.
+func (f *Fetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	sort, err := f.sort(input.Orders)
+	if err != nil {
+		return err
+	}
+	docs, err := f.Collection.Find(context.Background(), f.filter(cond), &FindOptions{
+		Sort:  sort,
+		Skip:  input.Offset,
+		Limit: input.Limit,
+	})
+	if err != nil {
+		return err
+	}
+	return f.decode(docs, result)
+}
+
+// Cursor returns the cursor of the record.
+func (f *Fetcher) Cursor(record interface{}) (string, error) {
+	doc, ok := record.(Document)
+	if f.Encode != nil {
+		var err error
+		doc, err = f.Encode(record)
+		if err != nil {
+			return "", err
+		}
+	} else if !ok {
+		return "", fmt.Errorf("docfetcher: record %T is not a Document", record)
+	}
+
+	keys := Document{f.keyField(): doc[f.keyField()]}
+	for _, field := range f.Fields {
+		keys[field] = doc[field]
+	}
+	return f.encodeCursor(keys)
+}
+
+// FetchKeyset fetches documents of the condition after or before the cursors.
+func (f *Fetcher) FetchKeyset(cond interface{}, input *pagination.KeysetInput, result *pagination.PageFetchResult) error {
+	sort, err := f.sort(input.Orders)
+	if err != nil {
+		return err
+	}
+
+	filters := []Document{}
+	if filter := f.filter(cond); len(filter) > 0 {
+		filters = append(filters, filter)
+	}
+	if input.After != "" {
+		keys, err := f.decodeCursor(input.After)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, keysetFilter(sort, keys, true))
+	}
+	if input.Before != "" {
+		keys, err := f.decodeCursor(input.Before)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, keysetFilter(sort, keys, false))
+	}
+
+	// the last documents are the first ones in the reversed order
+	if input.FromEnd {
+		for i := range sort {
+			sort[i].Order = -sort[i].Order
+		}
+	}
+
+	filter := Document{}
+	switch len(filters) {
+	case 0:
+	case 1:
+		filter = filters[0]
+	default:
+		filter = Document{"$and": filters}
+	}
+	docs, err := f.Collection.Find(context.Background(), filter, &FindOptions{Sort: sort, Limit: input.Limit})
+	if err != nil {
+		return err
+	}
+	if input.FromEnd {
+		for i, j := 0, len(docs)-1; i < j; i, j = i+1, j-1 {
+			docs[i], docs[j] = docs[j], docs[i]
+		}
+	}
+	return f.decode(docs, result)
+}
+
+func (f *Fetcher) filter(cond interface{}) Document {
+	if f.Filter == nil {
+		return Document{}
+	}
+	return f.Filter(cond)
+}
+
+func (f *Fetcher) keyField() string {
+	if f.KeyField == "" {
+		return "_id"
+	}
+	return f.KeyField
+}
+
+// sort returns the sort document of the orders, with the key field to break ties.
+// Without it, skip and limit over equal sort values are not deterministic, and pages may repeat or miss documents.
+func (f *Fetcher) sort(orders []*pagination.Order) ([]SortField, error) {
+	sort := make([]SortField, 0, len(orders)+1)
+	hasKey := false
+	for _, o := range orders {
+		field, ok := f.Fields[o.ColumnName]
+		if !ok {
+			return nil, fmt.Errorf("docfetcher: unknown sort column %q", o.ColumnName)
+		}
+		order := 1
+		if o.Direction == pagination.DirectionDesc {
+			order = -1
+		}
+		sort = append(sort, SortField{Key: field, Order: order})
+		hasKey = hasKey || field == f.keyField()
+	}
+	if !hasKey {
+		sort = append(sort, SortField{Key: f.keyField(), Order: 1})
+	}
+	return sort, nil
+}
+
+func (f *Fetcher) decode(docs []Document, result *pagination.PageFetchResult) error {
+	for _, doc := range docs {
+		if f.Decode == nil {
+			*result = append(*result, doc)
+			continue
+		}
+		record, err := f.Decode(doc)
+		if err != nil {
+			return err
+		}
+		*result = append(*result, record)
+	}
+	return nil
+}
+
+// keysetFilter returns the filter of documents after (or before) the keys in the sort order.
+func keysetFilter(sort []SortField, keys Document, after bool) Document {
+	or := make([]Document, 0, len(sort))
+	for i, s := range sort {
+		clause := Document{}
+		for _, prev := range sort[:i] {
+			clause[prev.Key] = keys[prev.Key]
+		}
+		op := "$gt"
+		if (s.Order < 0) == after {
+			op = "$lt"
+		}
+		clause[s.Key] = Document{op: keys[s.Key]}
+		or = append(or, clause)
+	}
+	return Document{"$or": or}
+}
@@ -0,0 +1,300 @@
+package docfetcher_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/docfetcher"
+)
+
+type fruit struct {
+	ID    int
+	Name  string
+	Price int
+}
+
+var dummyFruits = []fruit{
+	{1, "Apple", 112},
+	{2, "Pear", 245},
+	{3, "Banana", 60},
+	{4, "Orange", 80},
+	{5, "Kiwi", 106},
+	{6, "Strawberry", 350},
+	{7, "Grape", 400},
+	{8, "Grapefruit", 150},
+	{9, "Pineapple", 200},
+	{10, "Cherry", 140},
+	{11, "Mango", 200},
+}
+
+func newCollection() *docfetcher.MemoryCollection {
+	c := docfetcher.NewMemoryCollection()
+	for _, f := range dummyFruits {
+		c.Insert(docfetcher.Document{"_id": f.ID, "name": f.Name, "price": f.Price})
+	}
+	return c
+}
+
+// recordingCollection records options of Find.
+type recordingCollection struct {
+	docfetcher.Collection
+	finds []string
+	sorts []string
+}
+
+func (rc *recordingCollection) Find(ctx context.Context, filter docfetcher.Document, opts *docfetcher.FindOptions) ([]docfetcher.Document, error) {
+	rc.finds = append(rc.finds, fmt.Sprintf("skip=%d limit=%d", opts.Skip, opts.Limit))
+	rc.sorts = append(rc.sorts, fmt.Sprint(opts.Sort))
+	return rc.Collection.Find(ctx, filter, opts)
+}
+
+func newFruitFetcher(c docfetcher.Collection) *docfetcher.Fetcher {
+	return &docfetcher.Fetcher{
+		Collection: c,
+		Filter: func(cond interface{}) docfetcher.Document {
+			if low, ok := cond.(int); ok {
+				return docfetcher.Document{"price": docfetcher.Document{"$gte": low}}
+			}
+			return docfetcher.Document{}
+		},
+		Fields: map[string]string{"name": "name", "price": "price"},
+	}
+}
+
+func names(records pagination.PageFetchResult) []string {
+	names := []string{}
+	for _, r := range records {
+		switch r := r.(type) {
+		case docfetcher.Document:
+			names = append(names, r["name"].(string))
+		case fruit:
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	totalCount, pageCount, res, err := pagination.Fetch(newFruitFetcher(newCollection()), &pagination.Setting{
+		Limit:  2,
+		Page:   2,
+		Cond:   100,
+		Orders: []*pagination.Order{{Direction: pagination.DirectionDesc, ColumnName: "price"}, {Direction: pagination.DirectionAsc, ColumnName: "name"}},
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if totalCount != 9 || pageCount != 5 {
+		t.Errorf("Fetch() = %v, %v, want 9, 5", totalCount, pageCount)
+	}
+	want := map[string][]string{
+		"first":  {"Grape", "Strawberry"},
+		"active": {"Pear", "Mango"},
+		"last":   {"Kiwi"},
+	}
+	for name, want := range want {
+		if got := names(res.Pages[name]); !reflect.DeepEqual(got, want) {
+			t.Errorf("Fetch() pages.%v = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFetcher_FetchPageSort(t *testing.T) {
+	tests := []struct {
+		name   string
+		orders []*pagination.Order
+		want   string
+	}{
+		{"no orders", nil, "[{_id 1}]"},
+		{"ties", []*pagination.Order{{Direction: pagination.DirectionDesc, ColumnName: "price"}}, "[{price -1} {_id 1}]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &recordingCollection{Collection: newCollection()}
+			err := newFruitFetcher(c).FetchPage(nil, &pagination.PageFetchInput{Limit: 3, Offset: 3, Orders: tt.orders}, &pagination.PageFetchResult{})
+			if err != nil {
+				t.Fatalf("FetchPage() error = %v", err)
+			}
+			// the key field breaks ties, so skip and limit are deterministic
+			if want := []string{tt.want}; !reflect.DeepEqual(c.sorts, want) {
+				t.Errorf("FetchPage() sorts = %v, want %v", c.sorts, want)
+			}
+		})
+	}
+}
+
+func TestFetcher_Scan(t *testing.T) {
+	c := &recordingCollection{Collection: newCollection()}
+	it := pagination.Scan(context.Background(), newFruitFetcher(c), &pagination.ScanSetting{
+		Limit:  4,
+		Orders: []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: "price"}},
+	})
+	defer it.Close()
+
+	got := []string{}
+	for it.Next() {
+		got = append(got, it.Item().(docfetcher.Document)["name"].(string))
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	want := []string{"Banana", "Orange", "Kiwi", "Apple", "Cherry", "Grapefruit", "Pineapple", "Mango", "Pear", "Strawberry", "Grape"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() = %v, want %v", got, want)
+	}
+	// cursors are used instead of skip
+	if want := []string{"skip=0 limit=4", "skip=0 limit=4", "skip=0 limit=4"}; !reflect.DeepEqual(c.finds, want) {
+		t.Errorf("Scan() finds = %v, want %v", c.finds, want)
+	}
+}
+
+func TestFetcher_FetchKeyset(t *testing.T) {
+	fetcher := newFruitFetcher(newCollection())
+	orders := []*pagination.Order{{Direction: pagination.DirectionDesc, ColumnName: "price"}}
+	cursor := func(id int) string {
+		for _, f := range dummyFruits {
+			if f.ID == id {
+				c, err := fetcher.Cursor(docfetcher.Document{"_id": f.ID, "name": f.Name, "price": f.Price})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return c
+			}
+		}
+		panic(id)
+	}
+
+	tests := []struct {
+		name  string
+		input *pagination.KeysetInput
+		want  []string
+	}{
+		{"first", &pagination.KeysetInput{Limit: 3, Orders: orders}, []string{"Grape", "Strawberry", "Pear"}},
+		{"after tie", &pagination.KeysetInput{Limit: 3, After: cursor(9), Orders: orders}, []string{"Mango", "Grapefruit", "Cherry"}},
+		{"before", &pagination.KeysetInput{Limit: 3, Before: cursor(11), Orders: orders}, []string{"Grape", "Strawberry", "Pear"}},
+		{"last before", &pagination.KeysetInput{Limit: 2, Before: cursor(11), FromEnd: true, Orders: orders}, []string{"Pear", "Pineapple"}},
+		{"last", &pagination.KeysetInput{Limit: 2, FromEnd: true, Orders: orders}, []string{"Orange", "Banana"}},
+		{"between", &pagination.KeysetInput{Limit: 10, After: cursor(6), Before: cursor(10), Orders: orders}, []string{"Pear", "Pineapple", "Mango", "Grapefruit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := pagination.PageFetchResult{}
+			if err := fetcher.FetchKeyset(nil, tt.input, &result); err != nil {
+				t.Fatalf("FetchKeyset() error = %v", err)
+			}
+			if got := names(result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchKeyset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetcher_Decode(t *testing.T) {
+	fetcher := newFruitFetcher(newCollection())
+	fetcher.Decode = func(doc docfetcher.Document) (interface{}, error) {
+		return fruit{ID: doc["_id"].(int), Name: doc["name"].(string), Price: doc["price"].(int)}, nil
+	}
+	fetcher.Encode = func(record interface{}) (docfetcher.Document, error) {
+		f := record.(fruit)
+		return docfetcher.Document{"_id": f.ID, "name": f.Name, "price": f.Price}, nil
+	}
+
+	orders := []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: "name"}}
+	first := pagination.PageFetchResult{}
+	if err := fetcher.FetchKeyset(nil, &pagination.KeysetInput{Limit: 2, Orders: orders}, &first); err != nil {
+		t.Fatalf("FetchKeyset() error = %v", err)
+	}
+	cursor, err := fetcher.Cursor(first[1])
+	if err != nil {
+		t.Fatalf("Cursor() error = %v", err)
+	}
+	next := pagination.PageFetchResult{}
+	if err := fetcher.FetchKeyset(nil, &pagination.KeysetInput{Limit: 2, After: cursor, Orders: orders}, &next); err != nil {
+		t.Fatalf("FetchKeyset() error = %v", err)
+	}
+	if got, want := names(append(first, next...)), []string{"Apple", "Banana", "Cherry", "Grape"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchKeyset() = %v, want %v", got, want)
+	}
+}
+
+// objectID is a stand-in of ObjectIDs of MongoDB, which JSON does not restore by itself.
+type objectID [3]byte
+
+func TestFetcher_TypedCursor(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := docfetcher.NewMemoryCollection()
+	for i := 0; i < 7; i++ {
+		c.Insert(docfetcher.Document{
+			"_id":        objectID{0, 0, byte(i)},
+			"name":       fmt.Sprintf("fruit%d", i),
+			"created_at": base.Add(time.Duration(i/2) * time.Nanosecond),
+			"stock":      int64(1<<53 + i%2),
+		})
+	}
+
+	tests := []struct {
+		name  string
+		order string
+	}{
+		{"time.Time", "created_at"},
+		{"int64", "stock"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := &docfetcher.Fetcher{
+				Collection:  c,
+				Fields:      map[string]string{tt.order: tt.order},
+				CursorTypes: []interface{}{objectID{}},
+			}
+			it := pagination.Scan(context.Background(), fetcher, &pagination.ScanSetting{
+				Limit:  2,
+				Orders: []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: tt.order}},
+			})
+			defer it.Close()
+
+			count := 0
+			for it.Next() {
+				count++
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if count != 7 {
+				t.Errorf("Scan() count = %d, want 7", count)
+			}
+		})
+	}
+
+	// values of unknown types are rejected rather than compared as strings
+	fetcher := &docfetcher.Fetcher{Collection: c, Fields: map[string]string{"created_at": "created_at"}}
+	if _, err := fetcher.Cursor(docfetcher.Document{"_id": objectID{}, "created_at": base}); err == nil {
+		t.Errorf("Cursor() error = nil, want an error of unknown type")
+	}
+}
+
+func TestFetcher_Errors(t *testing.T) {
+	fetcher := newFruitFetcher(newCollection())
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"unknown column", fetcher.FetchPage(nil, &pagination.PageFetchInput{
+			Limit:  2,
+			Orders: []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: "secret"}},
+		}, &pagination.PageFetchResult{}), "unknown sort column"},
+		{"invalid cursor", fetcher.FetchKeyset(nil, &pagination.KeysetInput{Limit: 2, After: "!"}, &pagination.PageFetchResult{}), "cursor is invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err == nil || !strings.Contains(tt.err.Error(), tt.want) {
+				t.Errorf("error = %v, want %v", tt.err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package docfetcher
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryCollection is an in-memory Collection, which stands in for a document database in tests.
+// Filters support equality, $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin, $and and $or of top-level fields.
+// Numbers, strings, booleans and time.Time are comparable, and missing fields are less than any value.
+type MemoryCollection struct {
+	mu   sync.RWMutex
+	docs []Document
+}
+
+// NewMemoryCollection returns MemoryCollection of the documents.
+func NewMemoryCollection(docs ...Document) *MemoryCollection {
+	return &MemoryCollection{docs: docs}
+}
+
+// Insert inserts documents.
+func (c *MemoryCollection) Insert(docs ...Document) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.docs = append(c.docs, docs...)
+}
+
+// CountDocuments counts documents which match the filter.
+func (c *MemoryCollection) CountDocuments(ctx context.Context, filter Document) (int, error) {
+	docs, err := c.find(filter)
+	return len(docs), err
+}
+
+// Find returns documents which match the filter, in the sort order.
+func (c *MemoryCollection) Find(ctx context.Context, filter Document, opts *FindOptions) ([]Document, error) {
+	docs, err := c.find(filter)
+	if err != nil || opts == nil {
+		return docs, err
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, s := range opts.Sort {
+			if d := compare(docs[i][s.Key], docs[j][s.Key]); d != 0 {
+				return d*s.Order < 0
+			}
+		}
+		return false
+	})
+	skip := opts.Skip
+	if skip > len(docs) {
+		skip = len(docs)
+	}
+	docs = docs[skip:]
+	if opts.Limit > 0 && opts.Limit < len(docs) {
+		docs = docs[:opts.Limit]
+	}
+	return docs, nil
+}
+
+func (c *MemoryCollection) find(filter Document) ([]Document, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	docs := []Document{}
+	for _, doc := range c.docs {
+		ok, err := match(doc, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// match reports whether the document matches the filter.
+func match(doc Document, filter Document) (bool, error) {
+	for key, cond := range filter {
+		var ok bool
+		var err error
+		switch key {
+		case "$and", "$or":
+			ok, err = matchLogical(doc, key, cond)
+		default:
+			ok, err = matchField(doc[key], cond)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchLogical(doc Document, op string, cond interface{}) (bool, error) {
+	filters, err := documents(cond)
+	if err != nil {
+		return false, fmt.Errorf("docfetcher: %s: %v", op, err)
+	}
+	for _, filter := range filters {
+		ok, err := match(doc, filter)
+		if err != nil {
+			return false, err
+		}
+		if op == "$or" && ok {
+			return true, nil
+		}
+		if op == "$and" && !ok {
+			return false, nil
+		}
+	}
+	return op == "$and", nil
+}
+
+// matchField reports whether the value of a field matches the condition, which is a value or an operator document.
+func matchField(value interface{}, cond interface{}) (bool, error) {
+	ops, isDoc := asDocument(cond)
+	if !isDoc || !isOperators(ops) {
+		return compare(value, cond) == 0, nil
+	}
+
+	for op, operand := range ops {
+		var ok bool
+		switch op {
+		case "$eq":
+			ok = compare(value, operand) == 0
+		case "$ne":
+			ok = compare(value, operand) != 0
+		case "$gt":
+			ok = value != nil && compare(value, operand) > 0
+		case "$gte":
+			ok = value != nil && compare(value, operand) >= 0
+		case "$lt":
+			ok = value != nil && compare(value, operand) < 0
+		case "$lte":
+			ok = value != nil && compare(value, operand) <= 0
+		case "$in", "$nin":
+			in := false
+			rv := reflect.ValueOf(operand)
+			if rv.Kind() != reflect.Slice {
+				return false, fmt.Errorf("docfetcher: %s needs an array", op)
+			}
+			for i := 0; i < rv.Len(); i++ {
+				in = in || compare(value, rv.Index(i).Interface()) == 0
+			}
+			ok = in == (op == "$in")
+		default:
+			return false, fmt.Errorf("docfetcher: unsupported operator %s", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func asDocument(v interface{}) (Document, bool) {
+	switch d := v.(type) {
+	case Document:
+		return d, true
+	case map[string]interface{}:
+		return Document(d), true
+	}
+	return nil, false
+}
+
+func isOperators(doc Document) bool {
+	for key := range doc {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return len(doc) > 0
+}
+
+func documents(v interface{}) ([]Document, error) {
+	switch ds := v.(type) {
+	case []Document:
+		return ds, nil
+	case []interface{}:
+		docs := make([]Document, 0, len(ds))
+		for _, d := range ds {
+			doc, ok := asDocument(d)
+			if !ok {
+				return nil, fmt.Errorf("%T is not a document", d)
+			}
+			docs = append(docs, doc)
+		}
+		return docs, nil
+	}
+	return nil, fmt.Errorf("%T is not an array of documents", v)
+}
+
+// compare compares values. Values of different types are ordered by the type.
+func compare(a, b interface{}) int {
+	ta, va := typeOrder(a)
+	tb, vb := typeOrder(b)
+	if ta != tb {
+		return ta - tb
+	}
+	switch x := va.(type) {
+	case float64:
+		return compareFloat(x, vb.(float64))
+	case string:
+		return strings.Compare(x, vb.(string))
+	case bool:
+		y := vb.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+	case time.Time:
+		y := vb.(time.Time)
+		switch {
+		case x.Before(y):
+			return -1
+		case x.After(y):
+			return 1
+		}
+		return 0
+	case nil:
+		return 0
+	}
+	if reflect.DeepEqual(va, vb) {
+		return 0
+	}
+	return strings.Compare(fmt.Sprint(va), fmt.Sprint(vb))
+}
+
+func compareFloat(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// typeOrder returns the order of the type and the normalized value, like the BSON comparison order.
+func typeOrder(v interface{}) (int, interface{}) {
+	switch x := v.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		return 2, x
+	case bool:
+		return 4, x
+	case time.Time:
+		return 5, x
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return 1, float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return 1, float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return 1, rv.Float()
+	}
+	return 6, v
+}
@@ -0,0 +1,79 @@
+package docfetcher_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gemcook/pagination-go/docfetcher"
+)
+
+func TestMemoryCollection_Find(t *testing.T) {
+	type D = docfetcher.Document
+	day := func(d int) time.Time { return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC) }
+	c := docfetcher.NewMemoryCollection(
+		D{"_id": 1, "name": "Apple", "price": 112, "stock": true, "harvested": day(3)},
+		D{"_id": 2, "name": "Pear", "price": 245.5, "stock": false, "harvested": day(1)},
+		D{"_id": 3, "name": "Banana", "price": int64(60), "harvested": day(2)},
+		D{"_id": 4, "name": "Orange"},
+	)
+
+	tests := []struct {
+		name   string
+		filter D
+		opts   *docfetcher.FindOptions
+		want   []int
+	}{
+		{"all", D{}, nil, []int{1, 2, 3, 4}},
+		{"equal", D{"name": "Pear"}, nil, []int{2}},
+		{"equal number of other types", D{"price": 60.0}, nil, []int{3}},
+		{"range", D{"price": D{"$gte": 60, "$lt": 200}}, nil, []int{1, 3}},
+		{"missing fields do not match comparisons", D{"price": D{"$lt": 1000}}, nil, []int{1, 2, 3}},
+		{"not equal", D{"stock": D{"$ne": true}}, nil, []int{2, 3, 4}},
+		{"in", D{"name": D{"$in": []string{"Apple", "Orange"}}}, nil, []int{1, 4}},
+		{"not in", D{"_id": D{"$nin": []interface{}{1, 2}}}, nil, []int{3, 4}},
+		{"time", D{"harvested": D{"$gt": day(1)}}, nil, []int{1, 3}},
+		{"or", D{"$or": []D{{"name": "Apple"}, {"price": D{"$gt": 200}}}}, nil, []int{1, 2}},
+		{"and", D{"$and": []interface{}{map[string]interface{}{"price": D{"$gt": 50}}, D{"stock": D{"$eq": true}}}}, nil, []int{1}},
+		{"sort missing first", D{}, &docfetcher.FindOptions{Sort: []docfetcher.SortField{{Key: "price", Order: 1}}}, []int{4, 3, 1, 2}},
+		{"sort descending", D{}, &docfetcher.FindOptions{Sort: []docfetcher.SortField{{Key: "name", Order: -1}}}, []int{2, 4, 3, 1}},
+		{"skip and limit", D{}, &docfetcher.FindOptions{Sort: []docfetcher.SortField{{Key: "_id", Order: -1}}, Skip: 1, Limit: 2}, []int{3, 2}},
+		{"skip beyond", D{}, &docfetcher.FindOptions{Skip: 10}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := c.Find(context.Background(), tt.filter, tt.opts)
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			got := []int{}
+			for _, doc := range docs {
+				got = append(got, doc["_id"].(int))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryCollection_Errors(t *testing.T) {
+	type D = docfetcher.Document
+	c := docfetcher.NewMemoryCollection(D{"_id": 1})
+	tests := []struct {
+		name   string
+		filter D
+	}{
+		{"unsupported operator", D{"_id": D{"$regex": "a"}}},
+		{"in without array", D{"_id": D{"$in": 1}}},
+		{"or without documents", D{"$or": "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.CountDocuments(context.Background(), tt.filter); err == nil {
+				t.Errorf("CountDocuments() error = nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrInvalidPage is returned when the page is less than 1.
+	ErrInvalidPage = errors.New("page must be >= 1")
+	// ErrInvalidOffset is returned when the offset is negative.
+	ErrInvalidOffset = errors.New("offset must be >= 0")
+	// ErrInvalidLimit is returned when the limit is negative.
+	ErrInvalidLimit = errors.New("limit must be >= 0")
+	// ErrInvalidCursor is returned when a cursor or a page token given by the client can not be decoded.
+	ErrInvalidCursor = errors.New("cursor is invalid")
+	// ErrCursorNotAdvanced is returned by Iterator when KeysetFetcher returns the same cursor for the next page.
+	ErrCursorNotAdvanced = errors.New("keyset cursor did not advance")
+	// ErrInvalidBody is returned by ParseRequest when the JSON body is malformed.
+	ErrInvalidBody = errors.New("invalid JSON body")
+	// ErrNoFetcher is returned by GetPages of the pager made by NewPager.
+	ErrNoFetcher = errors.New("pager has no fetcher")
+	// ErrTimeout is returned by the fetcher decorated with WithTimeout when it takes too long.
+	ErrTimeout = errors.New("fetcher timed out")
+	// ErrCircuitOpen is returned by the fetcher decorated with WithCircuitBreaker while the circuit is open.
+	ErrCircuitOpen = errors.New("fetcher circuit is open")
+)
+
+// OutOfRangeError is returned when the page is beyond the last page.
+type OutOfRangeError struct {
+	Page      int
+	PageCount int
+}
+
+func (e *OutOfRangeError) Error() string {
+	return fmt.Sprintf("page is out of range. page range is 1-%v", e.PageCount)
+}
+
+// FetcherError is returned when Count or FetchPage of the fetcher fails.
+type FetcherError struct {
+	Stage Stage
+	Err   error
+}
+
+func (e *FetcherError) Error() string {
+	return fmt.Sprintf("pagination: %s: %v", e.Stage, e.Err)
+}
+
+// Unwrap returns the error of the fetcher.
+func (e *FetcherError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatus returns HTTP status code for the error of Fetch.
+//
+//	nil                                              -> 200 OK
+//	ErrInvalidPage, ErrInvalidOffset, ErrInvalidBody -> 400 Bad Request
+//	ErrInvalidLimit, ErrInvalidCursor                -> 400 Bad Request
+//	*OutOfRangeError                                 -> 404 Not Found
+//	ErrCircuitOpen                                   -> 503 Service Unavailable
+//	ErrTimeout                                       -> 504 Gateway Timeout
+//	*FetcherError and others                         -> 500 Internal Server Error
+func HTTPStatus(err error) int {
+	var outOfRange *OutOfRangeError
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, ErrInvalidPage), errors.Is(err, ErrInvalidOffset), errors.Is(err, ErrInvalidBody),
+		errors.Is(err, ErrInvalidLimit), errors.Is(err, ErrInvalidCursor):
+		return http.StatusBadRequest
+	case errors.As(err, &outOfRange):
+		return http.StatusNotFound
+	case errors.Is(err, ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrTimeout):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
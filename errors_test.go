@@ -0,0 +1,119 @@
+package pagination_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+func TestFetch_Errors(t *testing.T) {
+	t.Run("invalid page", func(t *testing.T) {
+		_, _, _, err := pagination.Fetch(newFruitFetcher(), &pagination.Setting{Page: -1})
+		if !errors.Is(err, pagination.ErrInvalidPage) {
+			t.Errorf("Fetch() error = %v, want %v", err, pagination.ErrInvalidPage)
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		_, _, _, err := pagination.Fetch(newFruitFetcher(), &pagination.Setting{Limit: -5})
+		if !errors.Is(err, pagination.ErrInvalidLimit) {
+			t.Errorf("Fetch() error = %v, want %v", err, pagination.ErrInvalidLimit)
+		}
+		if got := pagination.HTTPStatus(err); got != http.StatusBadRequest {
+			t.Errorf("HTTPStatus() = %v, want %v", got, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("invalid offset", func(t *testing.T) {
+		_, _, _, err := pagination.Fetch(newFruitFetcher(), &pagination.Setting{Offset: -1})
+		if !errors.Is(err, pagination.ErrInvalidOffset) {
+			t.Errorf("Fetch() error = %v, want %v", err, pagination.ErrInvalidOffset)
+		}
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		_, _, _, err := pagination.Fetch(newFruitFetcher(), &pagination.Setting{Limit: 2, Page: 100})
+		var outOfRange *pagination.OutOfRangeError
+		if !errors.As(err, &outOfRange) {
+			t.Fatalf("Fetch() error = %v, want *OutOfRangeError", err)
+		}
+		if outOfRange.Page != 100 || outOfRange.PageCount != 6 {
+			t.Errorf("Fetch() error = %+v, want Page 100 and PageCount 6", outOfRange)
+		}
+		if err.Error() != "page is out of range. page range is 1-6" {
+			t.Errorf("Fetch() error message = %v", err)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		fetcher   pagination.PageFetcher
+		wantStage pagination.Stage
+		wantMsg   string
+	}{
+		{"count error", &countFailingFetcher{}, pagination.StageCount, "pagination: count: count timeout"},
+		{"active error", &failingFetcher{}, pagination.StageActive, "pagination: active: connection reset"},
+		{"first error", &failingFetcher{successes: 1}, pagination.StageFirst, "pagination: first: connection reset"},
+		{"last error", &failingFetcher{successes: 2}, pagination.StageLast, "pagination: last: connection reset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := pagination.Fetch(tt.fetcher, &pagination.Setting{Limit: 10, Page: 5})
+			var fetcherErr *pagination.FetcherError
+			if !errors.As(err, &fetcherErr) {
+				t.Fatalf("Fetch() error = %v, want *FetcherError", err)
+			}
+			if fetcherErr.Stage != tt.wantStage {
+				t.Errorf("Fetch() error stage = %v, want %v", fetcherErr.Stage, tt.wantStage)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("Fetch() error message = %v, want %v", err, tt.wantMsg)
+			}
+			if errors.Unwrap(err) == nil {
+				t.Errorf("Fetch() error must wrap the fetcher error")
+			}
+		})
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	cause := errors.New("connection reset")
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 200},
+		{"invalid page", pagination.ErrInvalidPage, 400},
+		{"invalid offset", pagination.ErrInvalidOffset, 400},
+		{"invalid body", invalidBodyError(), 400},
+		{"invalid limit", fmt.Errorf("%w: first must be >= 0", pagination.ErrInvalidLimit), 400},
+		{"invalid cursor", &pagination.FetcherError{Stage: pagination.StageActive, Err: fmt.Errorf("decode: %w", pagination.ErrInvalidCursor)}, 400},
+		{"out of range", &pagination.OutOfRangeError{Page: 3, PageCount: 2}, 404},
+		{"wrapped out of range", fmt.Errorf("list fruits: %w", &pagination.OutOfRangeError{Page: 3, PageCount: 2}), 404},
+		{"circuit open", &pagination.FetcherError{Stage: pagination.StageCount, Err: pagination.ErrCircuitOpen}, 503},
+		{"timeout", &pagination.FetcherError{Stage: pagination.StageActive, Err: fmt.Errorf("%w after 1s", pagination.ErrTimeout)}, 504},
+		{"fetcher error", &pagination.FetcherError{Stage: pagination.StageCount, Err: cause}, 500},
+		{"unknown error", cause, 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pagination.HTTPStatus(tt.err); got != tt.want {
+				t.Errorf("HTTPStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// invalidBodyError returns the error of ParseRequest with a malformed JSON body.
+func invalidBodyError() error {
+	r := httptest.NewRequest(http.MethodPost, "/fruits", strings.NewReader(`{"limit": `))
+	r.Header.Set("Content-Type", "application/json")
+	_, err := pagination.ParseRequest(r)
+	return err
+}
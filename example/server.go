@@ -166,7 +166,7 @@ func handler(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		w.Header().Set("Content-Type", "text/html; charset=utf8")
-		w.WriteHeader(400)
+		w.WriteHeader(pagination.HTTPStatus(err))
 		fmt.Fprintf(w, "something wrong: %v", err)
 		return
 	}
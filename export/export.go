@@ -0,0 +1,198 @@
+// Package export streams all records of pagination.PageFetcher as CSV or newline-delimited JSON.
+package export
+
+import (
+	"context"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// Setting is export setting.
+type Setting struct {
+	Cond   interface{}
+	Orders []*pagination.Order
+	// data record count per single fetch. 100 is used if zero.
+	BatchSize int
+	// MaxRows stops the export after the rows are written. No limit if zero.
+	MaxRows int
+	// Progress is called with the count of written rows, after every BatchSize rows and at the end.
+	Progress func(rows int)
+	// Prefetch fetches the next page in background while the current page is written.
+	Prefetch bool
+}
+
+// WriteCSV writes all records of the fetcher as CSV, and returns the count of written rows.
+// Records must be structs or pointers to structs. The header is made from the first record,
+// with the field name or the name of `csv:"name"` tag. Fields tagged `csv:"-"` are skipped.
+// Nothing is written if there is no record.
+func WriteCSV(ctx context.Context, w io.Writer, fetcher pagination.PageFetcher, setting *Setting) (int, error) {
+	cw := csv.NewWriter(w)
+	var columns []column
+
+	rows, err := run(ctx, fetcher, setting, func(record interface{}) error {
+		v := reflect.Indirect(reflect.ValueOf(record))
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("record must be struct, but got %T", record)
+		}
+		if columns == nil {
+			columns = columnsOf(v.Type(), nil)
+			header := make([]string, 0, len(columns))
+			for _, c := range columns {
+				header = append(header, c.name)
+			}
+			if err := cw.Write(header); err != nil {
+				return err
+			}
+		}
+
+		row := make([]string, 0, len(columns))
+		for _, c := range columns {
+			f, ok := fieldByIndex(v, c.index)
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			s, err := format(f)
+			if err != nil {
+				return err
+			}
+			row = append(row, s)
+		}
+		return cw.Write(row)
+	}, func() error {
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return rows, err
+	}
+	cw.Flush()
+	return rows, cw.Error()
+}
+
+// WriteNDJSON writes all records of the fetcher as newline-delimited JSON, and returns the count of written rows.
+func WriteNDJSON(ctx context.Context, w io.Writer, fetcher pagination.PageFetcher, setting *Setting) (int, error) {
+	enc := json.NewEncoder(w)
+	return run(ctx, fetcher, setting, func(record interface{}) error {
+		return enc.Encode(record)
+	}, func() error {
+		return nil
+	})
+}
+
+// run writes records of the fetcher, and flushes after every batch.
+func run(ctx context.Context, fetcher pagination.PageFetcher, setting *Setting, write func(record interface{}) error, flush func() error) (int, error) {
+	batchSize := setting.BatchSize
+	if batchSize == 0 {
+		batchSize = 100
+	}
+	if setting.MaxRows != 0 && setting.MaxRows < batchSize {
+		batchSize = setting.MaxRows
+	}
+
+	it := pagination.Scan(ctx, fetcher, &pagination.ScanSetting{
+		Limit:    batchSize,
+		Cond:     setting.Cond,
+		Orders:   setting.Orders,
+		Prefetch: setting.Prefetch,
+	})
+	defer it.Close()
+
+	rows := 0
+	for (setting.MaxRows == 0 || rows < setting.MaxRows) && it.Next() {
+		if err := write(it.Item()); err != nil {
+			return rows, err
+		}
+		rows++
+
+		if rows%batchSize == 0 {
+			if err := flush(); err != nil {
+				return rows, err
+			}
+			if setting.Progress != nil {
+				setting.Progress(rows)
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return rows, err
+	}
+
+	if rows%batchSize != 0 && setting.Progress != nil {
+		setting.Progress(rows)
+	}
+	return rows, nil
+}
+
+type column struct {
+	name  string
+	index []int
+}
+
+// columnsOf returns exported fields of the struct type. Embedded structs are flattened.
+func columnsOf(t reflect.Type, parent []int) []column {
+	columns := []column{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := append(append([]int{}, parent...), i)
+		tag := f.Tag.Get("csv")
+		if tag == "-" {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if f.Anonymous && tag == "" && ft.Kind() == reflect.Struct {
+			columns = append(columns, columnsOf(ft, index)...)
+			continue
+		}
+
+		name := f.Name
+		if tag != "" {
+			name = tag
+		}
+		columns = append(columns, column{name, index})
+	}
+	return columns
+}
+
+// fieldByIndex returns the nested field, or false if it is in a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func format(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		return format(v.Elem())
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
@@ -0,0 +1,157 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/export"
+)
+
+type Audit struct {
+	CreatedAt time.Time `csv:"created_at" json:"created_at"`
+}
+
+type fruit struct {
+	Name   string  `csv:"name" json:"name"`
+	Price  int     `csv:"price" json:"price"`
+	Note   *string `json:"note,omitempty"`
+	Secret string  `csv:"-" json:"-"`
+	hidden string
+	*Audit
+}
+
+type fruitFetcher struct {
+	fruits  []*fruit
+	failAt  int
+	fetches int
+}
+
+func (ff *fruitFetcher) Count(cond interface{}) (int, error) {
+	return len(ff.fruits), nil
+}
+
+func (ff *fruitFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	ff.fetches++
+	if ff.failAt != 0 && ff.fetches >= ff.failAt {
+		return errors.New("connection reset")
+	}
+	toIndex := input.Offset + input.Limit
+	if toIndex > len(ff.fruits) {
+		toIndex = len(ff.fruits)
+	}
+	for _, f := range ff.fruits[input.Offset:toIndex] {
+		*result = append(*result, f)
+	}
+	return nil
+}
+
+func newFruits() []*fruit {
+	note := "seasonal"
+	created := time.Date(2018, 4, 1, 9, 0, 0, 0, time.UTC)
+	return []*fruit{
+		{Name: "Apple", Price: 112, Secret: "x"},
+		{Name: "Pear", Price: 245, Note: &note},
+		{Name: "Banana, ripe", Price: 60, Audit: &Audit{CreatedAt: created}},
+		{Name: "Orange", Price: 80},
+		{Name: "Kiwi", Price: 106},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	tests := []struct {
+		name         string
+		setting      *export.Setting
+		want         string
+		wantRows     int
+		wantProgress []int
+	}{
+		{"all rows", &export.Setting{BatchSize: 2}, "name,price,Note,created_at\n" +
+			"Apple,112,,\n" +
+			"Pear,245,seasonal,\n" +
+			"\"Banana, ripe\",60,,2018-04-01T09:00:00Z\n" +
+			"Orange,80,,\n" +
+			"Kiwi,106,,\n", 5, []int{2, 4, 5}},
+		{"max rows", &export.Setting{BatchSize: 2, MaxRows: 3}, "name,price,Note,created_at\n" +
+			"Apple,112,,\n" +
+			"Pear,245,seasonal,\n" +
+			"\"Banana, ripe\",60,,2018-04-01T09:00:00Z\n", 3, []int{2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var progress []int
+			tt.setting.Progress = func(rows int) {
+				progress = append(progress, rows)
+			}
+			var buf bytes.Buffer
+			rows, err := export.WriteCSV(context.Background(), &buf, &fruitFetcher{fruits: newFruits()}, tt.setting)
+			if err != nil {
+				t.Fatalf("WriteCSV() error = %v", err)
+			}
+			if rows != tt.wantRows {
+				t.Errorf("WriteCSV() rows = %v, want %v", rows, tt.wantRows)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("WriteCSV() =\n%v\nwant\n%v", buf.String(), tt.want)
+			}
+			if !reflect.DeepEqual(progress, tt.wantProgress) {
+				t.Errorf("WriteCSV() progress = %v, want %v", progress, tt.wantProgress)
+			}
+		})
+	}
+}
+
+func TestWriteCSV_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	rows, err := export.WriteCSV(context.Background(), &buf, &fruitFetcher{}, &export.Setting{})
+	if err != nil || rows != 0 || buf.Len() != 0 {
+		t.Errorf("WriteCSV() = %v, %v, %q, want nothing", rows, err, buf.String())
+	}
+}
+
+func TestWriteCSV_NotStruct(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := export.WriteCSV(context.Background(), &buf, &numberFetcher{}, &export.Setting{}); err == nil {
+		t.Errorf("WriteCSV() must fail for non struct records")
+	}
+}
+
+type numberFetcher struct{}
+
+func (nf *numberFetcher) Count(cond interface{}) (int, error) {
+	return 1, nil
+}
+
+func (nf *numberFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	*result = append(*result, 1)
+	return nil
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	rows, err := export.WriteNDJSON(context.Background(), &buf, &fruitFetcher{fruits: newFruits()}, &export.Setting{BatchSize: 2, MaxRows: 2})
+	if err != nil {
+		t.Fatalf("WriteNDJSON() error = %v", err)
+	}
+	want := `{"name":"Apple","price":112}` + "\n" + `{"name":"Pear","price":245,"note":"seasonal"}` + "\n"
+	if rows != 2 || buf.String() != want {
+		t.Errorf("WriteNDJSON() = %v, %v, want 2, %v", rows, buf.String(), want)
+	}
+}
+
+func TestWriteNDJSON_Error(t *testing.T) {
+	var buf bytes.Buffer
+	fetcher := &fruitFetcher{fruits: newFruits(), failAt: 2}
+	rows, err := export.WriteNDJSON(context.Background(), &buf, fetcher, &export.Setting{BatchSize: 2})
+	if err == nil || err.Error() != "connection reset" {
+		t.Errorf("WriteNDJSON() error = %v, want connection reset", err)
+	}
+	if rows != 2 || strings.Count(buf.String(), "\n") != 2 {
+		t.Errorf("WriteNDJSON() rows = %v, output = %v, want 2 rows", rows, buf.String())
+	}
+}
@@ -10,4 +10,4 @@ var CreateMockPager = func(limit, page, sidePagingCount, totalCount int) *Pager
 	return &pager
 }
 
-var NewPager = newPager
+var NewFetchingPager = newPager
@@ -0,0 +1,70 @@
+// Package gormfetcher provides pagination.PageFetcher of GORM.
+package gormfetcher
+
+import (
+	"fmt"
+	"reflect"
+
+	pagination "github.com/gemcook/pagination-go"
+	"gorm.io/gorm"
+)
+
+// Fetcher is pagination.PageFetcher of a *gorm.DB scope.
+type Fetcher struct {
+	// DB is the scope of records, e.g. db.Model(&Fruit{}).Where("price >= ?", 100).
+	DB *gorm.DB
+	// Scope narrows DB by the condition of pagination.Setting. DB is used as is if nil.
+	Scope func(db *gorm.DB, cond interface{}) *gorm.DB
+	// Records is the slice type which records are scanned into, e.g. []Fruit{}.
+	// Each element is appended to pagination.PageFetchResult.
+	Records interface{}
+	// Columns maps column names of pagination.Order to SQL expressions.
+	// Orders of other columns are rejected.
+	Columns map[string]string
+}
+
+// Count counts records of the condition.
+func (f *Fetcher) Count(cond interface{}) (int, error) {
+	var count int64
+	err := f.scope(cond).Count(&count).Error
+	return int(count), err
+}
+
+// FetchPage fetches records of the condition in the range of input.
+func (f *Fetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	db := f.scope(cond)
+	for _, o := range input.Orders {
+		expr, ok := f.Columns[o.ColumnName]
+		if !ok {
+			return fmt.Errorf("gormfetcher: unknown sort column %q", o.ColumnName)
+		}
+		d := pagination.DirectionAsc
+		if o.Direction == pagination.DirectionDesc {
+			d = pagination.DirectionDesc
+		}
+		db = db.Order(expr + " " + string(d))
+	}
+
+	records := reflect.New(reflect.TypeOf(f.Records))
+	err := db.Offset(input.Offset).Limit(input.Limit).Find(records.Interface()).Error
+	if err != nil {
+		return err
+	}
+	records = records.Elem()
+	for i := 0; i < records.Len(); i++ {
+		*result = append(*result, records.Index(i).Interface())
+	}
+	return nil
+}
+
+// scope returns a new session of DB narrowed by the condition.
+func (f *Fetcher) scope(cond interface{}) *gorm.DB {
+	db := f.DB.Session(&gorm.Session{})
+	if db.Statement.Model == nil {
+		db = db.Model(reflect.New(reflect.TypeOf(f.Records).Elem()).Interface())
+	}
+	if f.Scope != nil {
+		db = f.Scope(db, cond)
+	}
+	return db
+}
@@ -0,0 +1,150 @@
+package gormfetcher_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/gormfetcher"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type Fruit struct {
+	ID    uint
+	Name  string
+	Price int
+}
+
+var dummyFruits = []Fruit{
+	{Name: "Apple", Price: 112},
+	{Name: "Pear", Price: 245},
+	{Name: "Banana", Price: 60},
+	{Name: "Orange", Price: 80},
+	{Name: "Kiwi", Price: 106},
+	{Name: "Strawberry", Price: 350},
+	{Name: "Grape", Price: 400},
+	{Name: "Grapefruit", Price: 150},
+	{Name: "Pineapple", Price: 200},
+	{Name: "Cherry", Price: 140},
+	{Name: "Mango", Price: 199},
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "fruits.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&Fruit{}); err != nil {
+		t.Fatal(err)
+	}
+	fruits := append([]Fruit{}, dummyFruits...)
+	if err := db.Create(&fruits).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func newFruitFetcher(db *gorm.DB) *gormfetcher.Fetcher {
+	return &gormfetcher.Fetcher{
+		DB: db,
+		Scope: func(db *gorm.DB, cond interface{}) *gorm.DB {
+			if low, ok := cond.(int); ok {
+				return db.Where("price >= ?", low)
+			}
+			return db
+		},
+		Records: []Fruit{},
+		Columns: map[string]string{"price": "price", "name": "name"},
+	}
+}
+
+func names(records pagination.PageFetchResult) []string {
+	names := []string{}
+	for _, r := range records {
+		names = append(names, r.(Fruit).Name)
+	}
+	return names
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting *pagination.Setting
+		want    map[string][]string
+		total   int
+	}{
+		{"sorted by price", &pagination.Setting{
+			Limit:  2,
+			Page:   2,
+			Cond:   100,
+			Orders: []*pagination.Order{{Direction: pagination.DirectionDesc, ColumnName: "price"}},
+		}, map[string][]string{
+			"first":  {"Grape", "Strawberry"},
+			"active": {"Pear", "Pineapple"},
+			"last":   {"Kiwi"},
+		}, 9},
+		{"sorted by name", &pagination.Setting{
+			Limit:  3,
+			Page:   4,
+			Orders: []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: "name"}},
+		}, map[string][]string{
+			"first":  {"Apple", "Banana", "Cherry"},
+			"active": {"Pineapple", "Strawberry"},
+			"last":   {"Pineapple", "Strawberry"},
+		}, 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openDB(t)
+			totalCount, _, res, err := pagination.Fetch(newFruitFetcher(db), tt.setting)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if totalCount != tt.total {
+				t.Errorf("Fetch() totalCount = %v, want %v", totalCount, tt.total)
+			}
+			for name, want := range tt.want {
+				if got := names(res.Pages[name]); !reflect.DeepEqual(got, want) {
+					t.Errorf("Fetch() pages.%v = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFetcher_Scope(t *testing.T) {
+	db := openDB(t)
+	// the scope of DB is kept across calls
+	fetcher := newFruitFetcher(db.Model(&Fruit{}).Where("name LIKE ?", "%a%"))
+
+	for i := 0; i < 2; i++ {
+		count, err := fetcher.Count(200)
+		if err != nil || count != 4 {
+			t.Errorf("Count() = %v, %v, want 4", count, err)
+		}
+	}
+	result := pagination.PageFetchResult{}
+	err := fetcher.FetchPage(200, &pagination.PageFetchInput{Limit: 10}, &result)
+	if err != nil {
+		t.Fatalf("FetchPage() error = %v", err)
+	}
+	if got, want := names(result), []string{"Pear", "Strawberry", "Grape", "Pineapple"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchPage() = %v, want %v", got, want)
+	}
+}
+
+func TestFetcher_UnknownColumn(t *testing.T) {
+	db := openDB(t)
+	result := pagination.PageFetchResult{}
+	err := newFruitFetcher(db).FetchPage(nil, &pagination.PageFetchInput{
+		Limit:  2,
+		Orders: []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: "price; DROP TABLE fruits"}},
+	}, &result)
+	if err == nil || !strings.Contains(err.Error(), "unknown sort column") {
+		t.Errorf("FetchPage() error = %v, want unknown sort column", err)
+	}
+}
@@ -0,0 +1,150 @@
+package pagination
+
+import (
+	"context"
+	"time"
+)
+
+// Stage is a step of fetching pages.
+type Stage string
+
+const (
+	// StageCount counts all records.
+	StageCount Stage = "count"
+	// StageActive fetches the chunk of the active page and the side pages.
+	StageActive Stage = "active"
+	// StageFirst fetches the first page, when it is not in the active chunk.
+	StageFirst Stage = "first"
+	// StageLast fetches the last page, when it is not in the active chunk.
+	StageLast Stage = "last"
+	// StageRanges fetches the active chunk, the first and the last pages at once with MultiRangeFetcher.
+	StageRanges Stage = "ranges"
+	// StageBegin begins the snapshot of TxPageFetcher.
+	StageBegin Stage = "begin"
+	// StageCommit commits the snapshot of TxPageFetcher.
+	StageCommit Stage = "commit"
+)
+
+// Hooks are called around Count and FetchPage of the fetcher with the context of FetchContext.
+// Nil functions are skipped.
+type Hooks struct {
+	BeforeCount func(ctx context.Context, cond interface{})
+	AfterCount  func(ctx context.Context, cond interface{}, count int, elapsed time.Duration)
+	BeforeFetch func(ctx context.Context, stage Stage, input *PageFetchInput)
+	AfterFetch  func(ctx context.Context, stage Stage, input *PageFetchInput, rows int, elapsed time.Duration)
+	// OnError is called instead of AfterCount or AfterFetch when the fetcher fails.
+	// input is nil for StageCount.
+	// For StageRanges, input has the total limit of all ranges and the offset of the first range.
+	OnError func(ctx context.Context, stage Stage, input *PageFetchInput, err error, elapsed time.Duration)
+}
+
+// ComposeHooks returns hooks which call all of the given hooks in order.
+func ComposeHooks(hooks ...*Hooks) *Hooks {
+	return &Hooks{
+		BeforeCount: func(ctx context.Context, cond interface{}) {
+			for _, h := range hooks {
+				if h != nil && h.BeforeCount != nil {
+					h.BeforeCount(ctx, cond)
+				}
+			}
+		},
+		AfterCount: func(ctx context.Context, cond interface{}, count int, elapsed time.Duration) {
+			for _, h := range hooks {
+				if h != nil && h.AfterCount != nil {
+					h.AfterCount(ctx, cond, count, elapsed)
+				}
+			}
+		},
+		BeforeFetch: func(ctx context.Context, stage Stage, input *PageFetchInput) {
+			for _, h := range hooks {
+				if h != nil && h.BeforeFetch != nil {
+					h.BeforeFetch(ctx, stage, input)
+				}
+			}
+		},
+		AfterFetch: func(ctx context.Context, stage Stage, input *PageFetchInput, rows int, elapsed time.Duration) {
+			for _, h := range hooks {
+				if h != nil && h.AfterFetch != nil {
+					h.AfterFetch(ctx, stage, input, rows, elapsed)
+				}
+			}
+		},
+		OnError: func(ctx context.Context, stage Stage, input *PageFetchInput, err error, elapsed time.Duration) {
+			for _, h := range hooks {
+				if h != nil && h.OnError != nil {
+					h.OnError(ctx, stage, input, err, elapsed)
+				}
+			}
+		},
+	}
+}
+
+// count calls Count of the fetcher with hooks and metrics.
+func (p *Pager) count() (int, error) {
+	h := p.hooks
+	if h == nil {
+		h = &Hooks{}
+	}
+
+	if h.BeforeCount != nil {
+		h.BeforeCount(p.ctx, p.Condition)
+	}
+	start := time.Now()
+	var count int
+	var err error
+	if fetcher, ok := asLowerBoundPageFetcher(p.fetcher); ok {
+		count, p.totalLowerBound, err = fetcher.CountLowerBound(p.Condition)
+	} else {
+		count, err = p.fetcher.Count(p.Condition)
+	}
+	elapsed := time.Since(start)
+	if p.metrics != nil {
+		p.metrics.ObserveCount(elapsed, err)
+	}
+	if err != nil {
+		if h.OnError != nil {
+			h.OnError(p.ctx, StageCount, nil, err, elapsed)
+		}
+		return count, &FetcherError{Stage: StageCount, Err: err}
+	}
+	if h.AfterCount != nil {
+		h.AfterCount(p.ctx, p.Condition, count, elapsed)
+	}
+	return count, nil
+}
+
+// fetchPage calls FetchPage of the fetcher with hooks and metrics.
+func (p *Pager) fetchPage(stage Stage, input *PageFetchInput, result *PageFetchResult) error {
+	return p.observeFetch(stage, input, func() (int, error) {
+		err := p.fetcher.FetchPage(p.Condition, input, result)
+		return len(*result), err
+	})
+}
+
+// observeFetch calls fetch, which returns the count of fetched records of input, with hooks and metrics.
+func (p *Pager) observeFetch(stage Stage, input *PageFetchInput, fetch func() (int, error)) error {
+	h := p.hooks
+	if h == nil {
+		h = &Hooks{}
+	}
+
+	if h.BeforeFetch != nil {
+		h.BeforeFetch(p.ctx, stage, input)
+	}
+	start := time.Now()
+	rows, err := fetch()
+	elapsed := time.Since(start)
+	if p.metrics != nil {
+		p.metrics.ObserveFetch(stage, rows, elapsed, err)
+	}
+	if err != nil {
+		if h.OnError != nil {
+			h.OnError(p.ctx, stage, input, err, elapsed)
+		}
+		return &FetcherError{Stage: stage, Err: err}
+	}
+	if h.AfterFetch != nil {
+		h.AfterFetch(p.ctx, stage, input, rows, elapsed)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package pagination_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+type countFailingFetcher struct {
+	LargeDataFetcher
+}
+
+func (cf *countFailingFetcher) Count(cond interface{}) (int, error) {
+	return 0, errors.New("count timeout")
+}
+
+// recordingHooks returns hooks which record the calls as strings.
+func recordingHooks(calls *[]string) *pagination.Hooks {
+	return &pagination.Hooks{
+		BeforeCount: func(ctx context.Context, cond interface{}) {
+			*calls = append(*calls, "before count")
+		},
+		AfterCount: func(ctx context.Context, cond interface{}, count int, elapsed time.Duration) {
+			*calls = append(*calls, fmt.Sprintf("after count %v", count))
+		},
+		BeforeFetch: func(ctx context.Context, stage pagination.Stage, input *pagination.PageFetchInput) {
+			*calls = append(*calls, fmt.Sprintf("before %v %v/%v", stage, input.Limit, input.Offset))
+		},
+		AfterFetch: func(ctx context.Context, stage pagination.Stage, input *pagination.PageFetchInput, rows int, elapsed time.Duration) {
+			*calls = append(*calls, fmt.Sprintf("after %v %v/%v %v rows", stage, input.Limit, input.Offset, rows))
+		},
+		OnError: func(ctx context.Context, stage pagination.Stage, input *pagination.PageFetchInput, err error, elapsed time.Duration) {
+			*calls = append(*calls, fmt.Sprintf("error %v %v", stage, err))
+		},
+	}
+}
+
+func TestFetch_Hooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		fetcher pagination.PageFetcher
+		setting *pagination.Setting
+		want    []string
+	}{
+		{"all stages", newLargeDataFetcher(), &pagination.Setting{Limit: 10, Page: 5}, []string{
+			"before count",
+			"after count 103",
+			"before active 50/20",
+			"after active 50/20 50 rows",
+			"before first 10/0",
+			"after first 10/0 10 rows",
+			"before last 10/100",
+			"after last 10/100 3 rows",
+		}},
+		{"active only", newLargeDataFetcher(), &pagination.Setting{Limit: 50, Page: 1}, []string{
+			"before count",
+			"after count 103",
+			"before active 103/0",
+			"after active 103/0 103 rows",
+		}},
+		{"count error", &countFailingFetcher{}, &pagination.Setting{Limit: 10}, []string{
+			"before count",
+			"error count count timeout",
+		}},
+		{"fetch error", &failingFetcher{successes: 1}, &pagination.Setting{Limit: 10, Page: 5}, []string{
+			"before count",
+			"after count 103",
+			"before active 50/20",
+			"after active 50/20 50 rows",
+			"before first 10/0",
+			"error first connection reset",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := []string{}
+			tt.setting.Hooks = recordingHooks(&calls)
+			pagination.Fetch(tt.fetcher, tt.setting)
+			if !reflect.DeepEqual(calls, tt.want) {
+				t.Errorf("Fetch() hook calls = %q, want %q", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestComposeHooks(t *testing.T) {
+	first := []string{}
+	second := []string{}
+	hooks := pagination.ComposeHooks(recordingHooks(&first), nil, &pagination.Hooks{}, recordingHooks(&second))
+
+	_, _, _, err := pagination.Fetch(newFruitFetcher(), &pagination.Setting{Limit: 20, Hooks: hooks})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	want := []string{"before count", "after count 11", "before active 11/0", "after active 11/0 11 rows"}
+	if !reflect.DeepEqual(first, want) || !reflect.DeepEqual(second, want) {
+		t.Errorf("ComposeHooks() calls = %q and %q, want %q", first, second, want)
+	}
+}
@@ -0,0 +1,198 @@
+package pagination
+
+import "context"
+
+// ScanSetting is setting for Scan.
+type ScanSetting struct {
+	// data record count per single fetch. 100 is used if zero.
+	Limit  int
+	Cond   interface{}
+	Orders []*Order
+	// record offset to start from
+	Offset int
+	// Prefetch fetches the next page in background while the current page is read.
+	Prefetch bool
+}
+
+// Iterator walks all records of the fetcher page by page.
+//
+//	it := pagination.Scan(ctx, fetcher, &pagination.ScanSetting{Limit: 500})
+//	defer it.Close()
+//	for it.Next() {
+//		record := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	fetcher PageFetcher
+	setting ScanSetting
+
+	page    PageFetchResult
+	index   int
+	item    interface{}
+	next    scanPosition
+	pending chan scanPage
+	done    bool
+	err     error
+}
+
+// scanPosition is the start position of a page.
+type scanPosition struct {
+	offset int
+	after  string
+}
+
+type scanPage struct {
+	records PageFetchResult
+	err     error
+}
+
+// Scan returns an iterator over all records of the fetcher.
+// Records are fetched with increasing offsets, or with keyset cursors if the fetcher implements KeysetFetcher.
+// A negative Limit or Offset stops the iterator with ErrInvalidLimit or ErrInvalidOffset.
+func Scan(ctx context.Context, fetcher PageFetcher, setting *ScanSetting) *Iterator {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &Iterator{
+		ctx:     ctx,
+		cancel:  cancel,
+		fetcher: fetcher,
+		setting: *setting,
+	}
+	switch {
+	case it.setting.Limit < 0:
+		it.stop(ErrInvalidLimit)
+	case it.setting.Offset < 0:
+		it.stop(ErrInvalidOffset)
+	case it.setting.Limit == 0:
+		it.setting.Limit = 100
+	}
+	it.next.offset = it.setting.Offset
+	return it
+}
+
+// Next advances the iterator to the next record.
+// It returns false when all records are read, an error occurs or the context is canceled.
+func (it *Iterator) Next() bool {
+	for it.index >= len(it.page) {
+		if it.done || it.err != nil {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.stop(err)
+			return false
+		}
+
+		page, err := it.fetchNext()
+		if err != nil {
+			it.stop(err)
+			return false
+		}
+		it.page = page
+		it.index = 0
+	}
+
+	it.item = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Item returns the current record.
+func (it *Iterator) Item() interface{} {
+	return it.item
+}
+
+// Err returns the error which stopped the iterator.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator and its prefetching.
+func (it *Iterator) Close() {
+	it.done = true
+	it.page = nil
+	it.cancel()
+}
+
+func (it *Iterator) stop(err error) {
+	it.err = err
+	it.page = nil
+	it.cancel()
+}
+
+// fetchNext returns the next page, and starts prefetching the page after it.
+func (it *Iterator) fetchNext() (PageFetchResult, error) {
+	var page scanPage
+	if it.pending != nil {
+		select {
+		case page = <-it.pending:
+		case <-it.ctx.Done():
+			return nil, it.ctx.Err()
+		}
+		it.pending = nil
+	} else {
+		page = it.fetch(it.next)
+	}
+	if page.err != nil {
+		return nil, page.err
+	}
+
+	// a short page is the last page
+	if len(page.records) < it.setting.Limit {
+		it.done = true
+		return page.records, nil
+	}
+
+	next, err := it.positionAfter(page.records)
+	if err != nil {
+		return nil, err
+	}
+	it.next = next
+
+	if it.setting.Prefetch {
+		it.pending = make(chan scanPage, 1)
+		go func(pending chan<- scanPage, pos scanPosition) {
+			pending <- it.fetch(pos)
+		}(it.pending, next)
+	}
+	return page.records, nil
+}
+
+func (it *Iterator) positionAfter(records PageFetchResult) (scanPosition, error) {
+	next := scanPosition{offset: it.next.offset + len(records)}
+	if kf, ok := AsKeysetFetcher(it.fetcher); ok {
+		cursor, err := kf.Cursor(records[len(records)-1])
+		if err != nil {
+			return next, err
+		}
+		// the same cursor would fetch the same page forever
+		if cursor == it.next.after {
+			return next, ErrCursorNotAdvanced
+		}
+		next.after = cursor
+	}
+	return next, nil
+}
+
+func (it *Iterator) fetch(pos scanPosition) scanPage {
+	records := make(PageFetchResult, 0, it.setting.Limit)
+
+	if kf, ok := AsKeysetFetcher(it.fetcher); ok && (pos.after != "" || pos.offset == 0) {
+		input := &KeysetInput{
+			Limit:  it.setting.Limit,
+			After:  pos.after,
+			Orders: it.setting.Orders,
+		}
+		err := kf.FetchKeyset(it.setting.Cond, input, &records)
+		return scanPage{records, err}
+	}
+
+	input := &PageFetchInput{
+		Limit:  it.setting.Limit,
+		Offset: pos.offset,
+		Orders: it.setting.Orders,
+	}
+	err := it.fetcher.FetchPage(it.setting.Cond, input, &records)
+	return scanPage{records, err}
+}
@@ -0,0 +1,22 @@
+//go:build go1.23
+// +build go1.23
+
+package pagination
+
+import "iter"
+
+// All returns the records of the iterator as iter.Seq.
+// Check Err after the loop to know whether all records are read.
+//
+//	for record := range it.All() {
+//	}
+func (it *Iterator) All() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for it.Next() {
+			if !yield(it.Item()) {
+				it.Close()
+				return
+			}
+		}
+	}
+}
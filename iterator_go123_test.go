@@ -0,0 +1,27 @@
+//go:build go1.23
+// +build go1.23
+
+package pagination_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+func TestIterator_All(t *testing.T) {
+	it := pagination.Scan(context.Background(), newLargeDataFetcher(), &pagination.ScanSetting{Limit: 10})
+	ids := []int{}
+	it.All()(func(record interface{}) bool {
+		ids = append(ids, record.(LargeData).ID)
+		return len(ids) < 25
+	})
+	if !reflect.DeepEqual(ids, largeDataIDs(1, 25)) {
+		t.Errorf("Iterator.All() = %v, want %v", ids, largeDataIDs(1, 25))
+	}
+	if it.Next() {
+		t.Errorf("Iterator.Next() must be false after breaking the loop")
+	}
+}
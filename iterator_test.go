@@ -0,0 +1,199 @@
+package pagination_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// keysetLargeDataFetcher uses IDs as cursors.
+type keysetLargeDataFetcher struct {
+	LargeDataFetcher
+	offsetFetches int
+}
+
+func (kf *keysetLargeDataFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	kf.offsetFetches++
+	return kf.LargeDataFetcher.FetchPage(cond, input, result)
+}
+
+func (kf *keysetLargeDataFetcher) Cursor(record interface{}) (string, error) {
+	return strconv.Itoa(record.(LargeData).ID), nil
+}
+
+func (kf *keysetLargeDataFetcher) FetchKeyset(cond interface{}, input *pagination.KeysetInput, result *pagination.PageFetchResult) error {
+	after, _ := strconv.Atoi(input.After)
+	for _, d := range dummyLargeList {
+		if d.ID > after && len(*result) < input.Limit {
+			*result = append(*result, d)
+		}
+	}
+	return nil
+}
+
+// failingFetcher fails after fetching pages successfully.
+type failingFetcher struct {
+	LargeDataFetcher
+	successes int
+}
+
+func (ff *failingFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	if ff.successes == 0 {
+		return errors.New("connection reset")
+	}
+	ff.successes--
+	return ff.LargeDataFetcher.FetchPage(cond, input, result)
+}
+
+func scanIDs(it *pagination.Iterator) []int {
+	ids := []int{}
+	for it.Next() {
+		ids = append(ids, it.Item().(LargeData).ID)
+	}
+	return ids
+}
+
+func largeDataIDs(from, to int) []int {
+	ids := []int{}
+	for id := from; id <= to; id++ {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting *pagination.ScanSetting
+		want    []int
+	}{
+		{"default limit", &pagination.ScanSetting{}, largeDataIDs(1, 103)},
+		{"limit=10", &pagination.ScanSetting{Limit: 10}, largeDataIDs(1, 103)},
+		{"limit divides count", &pagination.ScanSetting{Limit: 103}, largeDataIDs(1, 103)},
+		{"offset", &pagination.ScanSetting{Limit: 10, Offset: 95}, largeDataIDs(96, 103)},
+		{"prefetch", &pagination.ScanSetting{Limit: 7, Prefetch: true}, largeDataIDs(1, 103)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := pagination.Scan(context.Background(), newLargeDataFetcher(), tt.setting)
+			defer it.Close()
+			if got := scanIDs(it); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() = %v, want %v", got, tt.want)
+			}
+			if err := it.Err(); err != nil {
+				t.Errorf("Scan() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestScan_Keyset(t *testing.T) {
+	fetcher := &keysetLargeDataFetcher{}
+	it := pagination.Scan(context.Background(), fetcher, &pagination.ScanSetting{Limit: 10, Prefetch: true})
+	defer it.Close()
+	if got := scanIDs(it); !reflect.DeepEqual(got, largeDataIDs(1, 103)) {
+		t.Errorf("Scan() = %v, want %v", got, largeDataIDs(1, 103))
+	}
+	if fetcher.offsetFetches != 0 {
+		t.Errorf("Scan() must fetch by keyset, but FetchPage is called %v times", fetcher.offsetFetches)
+	}
+}
+
+func TestScan_Error(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		t.Run("prefetch="+strconv.FormatBool(prefetch), func(t *testing.T) {
+			fetcher := &failingFetcher{successes: 2}
+			it := pagination.Scan(context.Background(), fetcher, &pagination.ScanSetting{Limit: 10, Prefetch: prefetch})
+			defer it.Close()
+			if got := scanIDs(it); !reflect.DeepEqual(got, largeDataIDs(1, 20)) {
+				t.Errorf("Scan() = %v, want %v", got, largeDataIDs(1, 20))
+			}
+			if err := it.Err(); err == nil || err.Error() != "connection reset" {
+				t.Errorf("Scan() error = %v, want connection reset", err)
+			}
+			if it.Next() {
+				t.Errorf("Scan() Next() must be false after error")
+			}
+		})
+	}
+}
+
+// stuckKeysetFetcher returns the same cursor for every record.
+type stuckKeysetFetcher struct {
+	keysetLargeDataFetcher
+}
+
+func (sf *stuckKeysetFetcher) Cursor(record interface{}) (string, error) {
+	return "0", nil
+}
+
+func TestScan_CursorNotAdvanced(t *testing.T) {
+	it := pagination.Scan(context.Background(), &stuckKeysetFetcher{}, &pagination.ScanSetting{Limit: 10})
+	defer it.Close()
+	if got := scanIDs(it); !reflect.DeepEqual(got, largeDataIDs(1, 10)) {
+		t.Errorf("Scan() = %v, want %v", got, largeDataIDs(1, 10))
+	}
+	if err := it.Err(); err != pagination.ErrCursorNotAdvanced {
+		t.Errorf("Scan() error = %v, want %v", err, pagination.ErrCursorNotAdvanced)
+	}
+}
+
+func TestScan_InvalidSetting(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting *pagination.ScanSetting
+		want    error
+	}{
+		{"negative limit", &pagination.ScanSetting{Limit: -1}, pagination.ErrInvalidLimit},
+		{"negative offset", &pagination.ScanSetting{Offset: -1}, pagination.ErrInvalidOffset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := pagination.Scan(context.Background(), newLargeDataFetcher(), tt.setting)
+			defer it.Close()
+			if it.Next() {
+				t.Errorf("Scan() Next() must be false")
+			}
+			if err := it.Err(); err != tt.want {
+				t.Errorf("Scan() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestScan_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := pagination.Scan(ctx, newLargeDataFetcher(), &pagination.ScanSetting{Limit: 10, Prefetch: true})
+	defer it.Close()
+
+	ids := []int{}
+	for it.Next() {
+		ids = append(ids, it.Item().(LargeData).ID)
+		if len(ids) == 15 {
+			cancel()
+		}
+	}
+	if !reflect.DeepEqual(ids, largeDataIDs(1, 20)) {
+		t.Errorf("Scan() = %v, want the rest of the fetched page %v", ids, largeDataIDs(1, 20))
+	}
+	if err := it.Err(); err != context.Canceled {
+		t.Errorf("Scan() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestIterator_Close(t *testing.T) {
+	it := pagination.Scan(context.Background(), newLargeDataFetcher(), &pagination.ScanSetting{Limit: 10, Prefetch: true})
+	it.Next()
+	it.Close()
+	if it.Next() {
+		t.Errorf("Iterator.Next() must be false after Close")
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Iterator.Err() = %v, want nil after Close", err)
+	}
+}
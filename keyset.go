@@ -0,0 +1,22 @@
+package pagination
+
+// KeysetFetcher is the interface to fetch records after or before keyset cursors.
+// Cursors are made by the fetcher itself, for example from the sort key of the record.
+type KeysetFetcher interface {
+	PageFetcher
+	// Cursor returns the cursor of the record.
+	Cursor(record interface{}) (string, error)
+	FetchKeyset(cond interface{}, input *KeysetInput, result *PageFetchResult) error
+}
+
+// KeysetInput input for keyset fetcher
+type KeysetInput struct {
+	Limit int
+	// After and Before are cursors given by KeysetFetcher.Cursor. Empty if not given.
+	After  string
+	Before string
+	// FromEnd requests the last Limit records before Before, instead of the first Limit records after After.
+	// Records must be in Orders either way.
+	FromEnd bool
+	Orders  []*Order
+}
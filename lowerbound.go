@@ -0,0 +1,11 @@
+package pagination
+
+// LowerBoundPageFetcher is a PageFetcher whose count can be a lower bound of the actual count,
+// like track_total_hits of search engines.
+// Pager calls CountLowerBound instead of Count, and reports it with PagingResponse.TotalLowerBound.
+// The page count and the last page are the ones of the lower bound then.
+type LowerBoundPageFetcher interface {
+	PageFetcher
+	// CountLowerBound counts records of the condition, and reports whether the count is a lower bound.
+	CountLowerBound(cond interface{}) (count int, lowerBound bool, err error)
+}
@@ -0,0 +1,48 @@
+package pagination_test
+
+import (
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// lowerBoundLargeDataFetcher counts up to 100 records.
+type lowerBoundLargeDataFetcher struct {
+	LargeDataFetcher
+}
+
+func (lf *lowerBoundLargeDataFetcher) CountLowerBound(cond interface{}) (int, bool, error) {
+	count, err := lf.Count(cond)
+	if count > 100 {
+		return 100, true, err
+	}
+	return count, false, err
+}
+
+func TestFetch_LowerBoundPageFetcher(t *testing.T) {
+	tests := []struct {
+		name           string
+		fetcher        pagination.PageFetcher
+		wantTotal      int
+		wantPages      int
+		wantLowerBound bool
+		wantLast       int
+	}{
+		{"lower bound", &lowerBoundLargeDataFetcher{}, 100, 10, true, 91},
+		{"exact", newLargeDataFetcher(), 103, 11, false, 101},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totalCount, pageCount, res, err := pagination.Fetch(tt.fetcher, &pagination.Setting{Limit: 10, Page: 1})
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if totalCount != tt.wantTotal || pageCount != tt.wantPages || res.TotalLowerBound != tt.wantLowerBound {
+				t.Errorf("Fetch() = %v, %v, %v, want %v, %v, %v", totalCount, pageCount, res.TotalLowerBound, tt.wantTotal, tt.wantPages, tt.wantLowerBound)
+			}
+			if got := res.Pages["last"][0].(LargeData).ID; got != tt.wantLast {
+				t.Errorf("Fetch() pages.last starts with %v, want %v", got, tt.wantLast)
+			}
+		})
+	}
+}
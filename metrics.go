@@ -0,0 +1,14 @@
+package pagination
+
+import "time"
+
+// MetricsCollector collects metrics of Fetch.
+// See package metrics for an implementation with Prometheus text exposition.
+type MetricsCollector interface {
+	// ObserveRequest is called once per fetch with the limit and the active page number.
+	ObserveRequest(limit, page int)
+	// ObserveCount is called after Count, or after FetchPageWithCount of CountingPageFetcher.
+	ObserveCount(elapsed time.Duration, err error)
+	// ObserveFetch is called after FetchPage with the count of returned rows.
+	ObserveFetch(stage Stage, rows int, elapsed time.Duration, err error)
+}
@@ -0,0 +1,237 @@
+// Package metrics collects metrics of pagination.Fetch, and exposes them in Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// Options is options of Collector. Zero values are replaced with defaults.
+type Options struct {
+	// Namespace is the prefix of metric names. "pagination" is used if empty.
+	Namespace string
+	// LimitBuckets are upper bounds of limit to count requests.
+	LimitBuckets []float64
+	// PageBuckets are upper bounds of the page depth histogram.
+	PageBuckets []float64
+	// DurationBuckets are upper bounds of latency histograms in seconds.
+	DurationBuckets []float64
+}
+
+var (
+	defaultLimitBuckets    = []float64{10, 20, 50, 100, 500}
+	defaultPageBuckets     = []float64{1, 2, 3, 5, 10, 20, 50, 100}
+	defaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+// Collector implements pagination.MetricsCollector.
+// It is safe for concurrent use.
+type Collector struct {
+	namespace    string
+	limitBuckets []float64
+	durBuckets   []float64
+
+	mu            sync.Mutex
+	requests      map[string]uint64
+	pageDepth     *histogram
+	countDuration *histogram
+	fetchDuration map[string]*histogram
+	rows          map[string]uint64
+	errors        map[string]uint64
+}
+
+// NewCollector returns an empty collector. opts can be nil.
+func NewCollector(opts *Options) *Collector {
+	if opts == nil {
+		opts = &Options{}
+	}
+	c := &Collector{
+		namespace:     opts.Namespace,
+		limitBuckets:  opts.LimitBuckets,
+		durBuckets:    opts.DurationBuckets,
+		requests:      map[string]uint64{},
+		fetchDuration: map[string]*histogram{},
+		rows:          map[string]uint64{},
+		errors:        map[string]uint64{},
+	}
+	if c.namespace == "" {
+		c.namespace = "pagination"
+	}
+	if c.limitBuckets == nil {
+		c.limitBuckets = defaultLimitBuckets
+	}
+	if c.durBuckets == nil {
+		c.durBuckets = defaultDurationBuckets
+	}
+	pageBuckets := opts.PageBuckets
+	if pageBuckets == nil {
+		pageBuckets = defaultPageBuckets
+	}
+	c.pageDepth = newHistogram(pageBuckets)
+	c.countDuration = newHistogram(c.durBuckets)
+	return c
+}
+
+// ObserveRequest counts the request by limit bucket, and observes the page depth.
+func (c *Collector) ObserveRequest(limit, page int) {
+	bucket := "+Inf"
+	for _, b := range c.limitBuckets {
+		if float64(limit) <= b {
+			bucket = formatFloat(b)
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests[bucket]++
+	c.pageDepth.observe(float64(page))
+}
+
+// ObserveCount observes the latency of Count.
+func (c *Collector) ObserveCount(elapsed time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.countDuration.observe(elapsed.Seconds())
+	if err != nil {
+		c.errors[string(pagination.StageCount)]++
+	}
+}
+
+// ObserveFetch observes the latency and the returned rows of FetchPage.
+func (c *Collector) ObserveFetch(stage pagination.Stage, rows int, elapsed time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, ok := c.fetchDuration[string(stage)]
+	if !ok {
+		h = newHistogram(c.durBuckets)
+		c.fetchDuration[string(stage)] = h
+	}
+	h.observe(elapsed.Seconds())
+	c.rows[string(stage)] += uint64(rows)
+	if err != nil {
+		c.errors[string(stage)]++
+	}
+}
+
+// WriteTo writes the metrics in Prometheus text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	var b strings.Builder
+	ns := c.namespace
+
+	writeHeader(&b, ns+"_requests_total", "counter", "Count of paginated requests by limit bucket.")
+	limits := make([]string, 0, len(c.limitBuckets)+1)
+	for _, l := range c.limitBuckets {
+		limits = append(limits, formatFloat(l))
+	}
+	limits = append(limits, "+Inf")
+	for _, l := range limits {
+		fmt.Fprintf(&b, "%s_requests_total{limit=%q} %d\n", ns, l, c.requests[l])
+	}
+
+	writeHeader(&b, ns+"_page_depth", "histogram", "Requested active page number.")
+	c.pageDepth.write(&b, ns+"_page_depth", "")
+
+	writeHeader(&b, ns+"_count_duration_seconds", "histogram", "Latency of Count.")
+	c.countDuration.write(&b, ns+"_count_duration_seconds", "")
+
+	stages := make([]string, 0, len(c.fetchDuration))
+	for stage := range c.fetchDuration {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+
+	writeHeader(&b, ns+"_fetch_duration_seconds", "histogram", "Latency of FetchPage by stage.")
+	for _, stage := range stages {
+		c.fetchDuration[stage].write(&b, ns+"_fetch_duration_seconds", fmt.Sprintf("stage=%q", stage))
+	}
+
+	writeHeader(&b, ns+"_rows_total", "counter", "Count of rows returned by FetchPage by stage.")
+	for _, stage := range stages {
+		fmt.Fprintf(&b, "%s_rows_total{stage=%q} %d\n", ns, stage, c.rows[stage])
+	}
+
+	errorStages := make([]string, 0, len(c.errors))
+	for stage := range c.errors {
+		errorStages = append(errorStages, stage)
+	}
+	sort.Strings(errorStages)
+
+	writeHeader(&b, ns+"_errors_total", "counter", "Count of fetcher errors by stage.")
+	for _, stage := range errorStages {
+		fmt.Fprintf(&b, "%s_errors_total{stage=%q} %d\n", ns, stage, c.errors[stage])
+	}
+	c.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP serves the metrics to Prometheus scraping.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+func writeHeader(b *strings.Builder, name, typ, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, typ)
+}
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// write writes the cumulative buckets, sum and count with the extra labels.
+func (h *histogram) write(b *strings.Builder, name, labels string) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+	for i, bound := range h.buckets {
+		fmt.Fprintf(b, "%s_bucket{%s%sle=%q} %d\n", name, labels, sep, formatFloat(bound), h.counts[i])
+	}
+	fmt.Fprintf(b, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, h.count)
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(b, "%s_sum%s %s\n", name, labels, formatFloat(h.sum))
+	fmt.Fprintf(b, "%s_count%s %d\n", name, labels, h.count)
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,126 @@
+package metrics_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/metrics"
+)
+
+type numberFetcher struct {
+	count int
+}
+
+func (nf *numberFetcher) Count(cond interface{}) (int, error) {
+	return nf.count, nil
+}
+
+func (nf *numberFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	for i := input.Offset; i < input.Offset+input.Limit && i < nf.count; i++ {
+		*result = append(*result, i)
+	}
+	return nil
+}
+
+func TestCollector_WriteTo(t *testing.T) {
+	c := metrics.NewCollector(&metrics.Options{
+		Namespace:       "api",
+		LimitBuckets:    []float64{10, 50},
+		PageBuckets:     []float64{1, 5},
+		DurationBuckets: []float64{0.01, 0.1},
+	})
+	c.ObserveRequest(10, 1)
+	c.ObserveRequest(20, 3)
+	c.ObserveRequest(100, 8)
+	c.ObserveCount(5*time.Millisecond, nil)
+	c.ObserveCount(50*time.Millisecond, errors.New("timeout"))
+	c.ObserveFetch(pagination.StageActive, 50, 20*time.Millisecond, nil)
+	c.ObserveFetch(pagination.StageLast, 3, 200*time.Millisecond, nil)
+	c.ObserveFetch(pagination.StageActive, 0, 2*time.Millisecond, errors.New("reset"))
+
+	want := `# HELP api_requests_total Count of paginated requests by limit bucket.
+# TYPE api_requests_total counter
+api_requests_total{limit="10"} 1
+api_requests_total{limit="50"} 1
+api_requests_total{limit="+Inf"} 1
+# HELP api_page_depth Requested active page number.
+# TYPE api_page_depth histogram
+api_page_depth_bucket{le="1"} 1
+api_page_depth_bucket{le="5"} 2
+api_page_depth_bucket{le="+Inf"} 3
+api_page_depth_sum 12
+api_page_depth_count 3
+# HELP api_count_duration_seconds Latency of Count.
+# TYPE api_count_duration_seconds histogram
+api_count_duration_seconds_bucket{le="0.01"} 1
+api_count_duration_seconds_bucket{le="0.1"} 2
+api_count_duration_seconds_bucket{le="+Inf"} 2
+api_count_duration_seconds_sum 0.055
+api_count_duration_seconds_count 2
+# HELP api_fetch_duration_seconds Latency of FetchPage by stage.
+# TYPE api_fetch_duration_seconds histogram
+api_fetch_duration_seconds_bucket{stage="active",le="0.01"} 1
+api_fetch_duration_seconds_bucket{stage="active",le="0.1"} 2
+api_fetch_duration_seconds_bucket{stage="active",le="+Inf"} 2
+api_fetch_duration_seconds_sum{stage="active"} 0.022
+api_fetch_duration_seconds_count{stage="active"} 2
+api_fetch_duration_seconds_bucket{stage="last",le="0.01"} 0
+api_fetch_duration_seconds_bucket{stage="last",le="0.1"} 0
+api_fetch_duration_seconds_bucket{stage="last",le="+Inf"} 1
+api_fetch_duration_seconds_sum{stage="last"} 0.2
+api_fetch_duration_seconds_count{stage="last"} 1
+# HELP api_rows_total Count of rows returned by FetchPage by stage.
+# TYPE api_rows_total counter
+api_rows_total{stage="active"} 50
+api_rows_total{stage="last"} 3
+# HELP api_errors_total Count of fetcher errors by stage.
+# TYPE api_errors_total counter
+api_errors_total{stage="active"} 1
+api_errors_total{stage="count"} 1
+`
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("Collector.WriteTo() error = %v", err)
+	}
+	if buf.String() != want {
+		t.Errorf("Collector.WriteTo() =\n%v\nwant\n%v", buf.String(), want)
+	}
+}
+
+func TestCollector_Fetch(t *testing.T) {
+	c := metrics.NewCollector(nil)
+	for _, page := range []int{1, 5} {
+		_, _, _, err := pagination.Fetch(&numberFetcher{count: 103}, &pagination.Setting{Limit: 10, Page: page, Metrics: c})
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Collector.ServeHTTP() Content-Type = %v", ct)
+	}
+
+	body := rec.Body.String()
+	for _, line := range []string{
+		`pagination_requests_total{limit="10"} 2`,
+		`pagination_page_depth_sum 6`,
+		`pagination_count_duration_seconds_count 2`,
+		`pagination_fetch_duration_seconds_count{stage="active"} 2`,
+		`pagination_fetch_duration_seconds_count{stage="first"} 1`,
+		`pagination_fetch_duration_seconds_count{stage="last"} 2`,
+		`pagination_rows_total{stage="active"} 100`,
+		`pagination_rows_total{stage="first"} 10`,
+		`pagination_rows_total{stage="last"} 6`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Collector.ServeHTTP() must contain %q, got\n%v", line, body)
+		}
+	}
+}
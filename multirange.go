@@ -0,0 +1,86 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRangeMismatch is returned when MultiRangeFetcher returns records which do not fit the ranges.
+var ErrRangeMismatch = errors.New("records do not match the ranges")
+
+// MultiRangeFetcher is a PageFetcher which can fetch disjoint ranges of records at once,
+// e.g. with a single UNION ALL query or a single multi-get.
+// Pager fetches the active chunk, the first and the last pages with FetchRanges instead of calling FetchPage for each.
+type MultiRangeFetcher interface {
+	PageFetcher
+	// FetchRanges fetches records in all ranges of inputs, and appends records of each range to the result of the same index.
+	FetchRanges(cond interface{}, inputs []*PageFetchInput, results []*PageFetchResult) error
+}
+
+// pageFetch is a range of records to fetch into result.
+type pageFetch struct {
+	stage  Stage
+	input  *PageFetchInput
+	result *PageFetchResult
+}
+
+// fetchPages fetches all ranges, at once if the fetcher is MultiRangeFetcher.
+func (p *Pager) fetchPages(fetches []pageFetch) error {
+	fetcher, ok := asMultiRangeFetcher(p.fetcher)
+	if !ok || len(fetches) < 2 {
+		for _, f := range fetches {
+			err := p.fetchPage(f.stage, f.input, f.result)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	inputs := make([]*PageFetchInput, len(fetches))
+	results := make([]*PageFetchResult, len(fetches))
+	total := &PageFetchInput{Offset: fetches[0].input.Offset, Orders: p.Orders}
+	for i, f := range fetches {
+		inputs[i] = f.input
+		results[i] = f.result
+		total.Limit += f.input.Limit
+	}
+	err := p.observeFetch(StageRanges, total, func() (int, error) {
+		err := fetcher.FetchRanges(p.Condition, inputs, results)
+		rows := 0
+		for _, result := range results {
+			rows += len(*result)
+		}
+		return rows, err
+	})
+	if err != nil {
+		return err
+	}
+
+	// a range never has more records than its limit, unless it has records of other ranges.
+	// The exact count is expected only in a snapshot with the exact total count,
+	// since records may be inserted or deleted after the count otherwise, as FetchPage tolerates.
+	exact := p.inSnapshot && !p.totalLowerBound
+	for _, f := range fetches {
+		got := len(*f.result)
+		if got > f.input.Limit {
+			err := fmt.Errorf("%w: %s range has %d records, want at most %d", ErrRangeMismatch, f.stage, got, f.input.Limit)
+			return &FetcherError{Stage: StageRanges, Err: err}
+		}
+		if !exact {
+			continue
+		}
+		n := f.input.Limit
+		if rest := p.totalCount - f.input.Offset; rest < n {
+			n = rest
+		}
+		if n < 0 {
+			n = 0
+		}
+		if got != n {
+			err := fmt.Errorf("%w: %s range has %d records, want %d", ErrRangeMismatch, f.stage, got, n)
+			return &FetcherError{Stage: StageRanges, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package pagination_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// multiRangeLargeDataFetcher fetches ranges at once.
+type multiRangeLargeDataFetcher struct {
+	LargeDataFetcher
+	calls []string
+	// drop drops the last record of the active range, and extra adds a record to it
+	drop, extra bool
+}
+
+func (mf *multiRangeLargeDataFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	mf.calls = append(mf.calls, fmt.Sprintf("fetch %d+%d", input.Offset, input.Limit))
+	return mf.LargeDataFetcher.FetchPage(cond, input, result)
+}
+
+func (mf *multiRangeLargeDataFetcher) FetchRanges(cond interface{}, inputs []*pagination.PageFetchInput, results []*pagination.PageFetchResult) error {
+	call := "fetch ranges"
+	for i, input := range inputs {
+		call += fmt.Sprintf(" %d+%d", input.Offset, input.Limit)
+		if err := mf.LargeDataFetcher.FetchPage(cond, input, results[i]); err != nil {
+			return err
+		}
+	}
+	mf.calls = append(mf.calls, call)
+	if mf.drop {
+		*results[0] = (*results[0])[:len(*results[0])-1]
+	}
+	if mf.extra {
+		*results[0] = append(*results[0], "extra")
+	}
+	return nil
+}
+
+// snapshotMultiRangeFetcher fetches ranges at once in snapshots.
+type snapshotMultiRangeFetcher struct {
+	multiRangeLargeDataFetcher
+	// lowerBound counts up to 95 records in snapshots
+	lowerBound bool
+}
+
+func (sf *snapshotMultiRangeFetcher) Begin(ctx context.Context) (pagination.PageFetcherTx, error) {
+	tx := &multiRangeTx{&sf.multiRangeLargeDataFetcher}
+	if sf.lowerBound {
+		return &lowerBoundMultiRangeTx{tx}, nil
+	}
+	return tx, nil
+}
+
+type multiRangeTx struct {
+	*multiRangeLargeDataFetcher
+}
+
+func (tx *multiRangeTx) Commit() error   { return nil }
+func (tx *multiRangeTx) Rollback() error { return nil }
+
+type lowerBoundMultiRangeTx struct {
+	*multiRangeTx
+}
+
+func (tx *lowerBoundMultiRangeTx) CountLowerBound(cond interface{}) (int, bool, error) {
+	return 95, true, nil
+}
+
+func TestFetch_MultiRangeFetcher(t *testing.T) {
+	tests := []struct {
+		name      string
+		setting   *pagination.Setting
+		wantCalls []string
+	}{
+		{"active only", &pagination.Setting{Limit: 50, Page: 1}, []string{"fetch 0+103"}},
+		{"active and last", &pagination.Setting{Limit: 10, Page: 1}, []string{"fetch ranges 0+50 100+10"}},
+		{"active, first and last", &pagination.Setting{Limit: 10, Page: 5}, []string{"fetch ranges 20+50 0+10 100+10"}},
+		{"unaligned offset", &pagination.Setting{Limit: 10, Offset: 35}, []string{"fetch ranges 15+50 0+5 95+10"}},
+		{"first and last of empty page", &pagination.Setting{Limit: 10, Page: 20, OutOfRange: pagination.OutOfRangeEmpty}, []string{"fetch ranges 0+10 100+10"}},
+		{"single page of empty page", &pagination.Setting{Limit: 200, Page: 2, OutOfRange: pagination.OutOfRangeEmpty}, []string{"fetch 0+200"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := &multiRangeLargeDataFetcher{}
+			_, _, res, err := pagination.Fetch(fetcher, tt.setting)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if !reflect.DeepEqual(fetcher.calls, tt.wantCalls) {
+				t.Errorf("Fetch() calls = %q, want %q", fetcher.calls, tt.wantCalls)
+			}
+
+			_, _, want, err := pagination.Fetch(newLargeDataFetcher(), tt.setting)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if !reflect.DeepEqual(res, want) {
+				t.Errorf("Fetch() = %v, want %v", res, want)
+			}
+		})
+	}
+}
+
+func TestFetch_MultiRangeFetcherMismatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		fetcher pagination.PageFetcher
+		wantErr string
+	}{
+		{"long range", &multiRangeLargeDataFetcher{extra: true}, "active range has 51 records, want at most 50"},
+		// records may be deleted after the count, as FetchPage tolerates
+		{"short range", &multiRangeLargeDataFetcher{drop: true}, ""},
+		// the short range fails by itself, instead of taking records of the next range
+		{"short range in snapshot", &snapshotMultiRangeFetcher{multiRangeLargeDataFetcher: multiRangeLargeDataFetcher{drop: true}}, "active range has 49 records, want 50"},
+		// the last page beyond the lower bound has more records than the lower bound tells
+		{"lower bound in snapshot", &snapshotMultiRangeFetcher{lowerBound: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := pagination.Fetch(tt.fetcher, &pagination.Setting{Limit: 10, Page: 5})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Fetch() error = %v", err)
+				}
+				return
+			}
+			var fetcherErr *pagination.FetcherError
+			if !errors.As(err, &fetcherErr) || fetcherErr.Stage != pagination.StageRanges || !errors.Is(err, pagination.ErrRangeMismatch) {
+				t.Errorf("Fetch() error = %v, want ErrRangeMismatch of ranges stage", err)
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Fetch() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetch_MultiRangeFetcherHooks(t *testing.T) {
+	var got []string
+	hooks := &pagination.Hooks{
+		AfterFetch: func(ctx context.Context, stage pagination.Stage, input *pagination.PageFetchInput, rows int, elapsed time.Duration) {
+			got = append(got, fmt.Sprintf("%s %d+%d rows=%d", stage, input.Offset, input.Limit, rows))
+		},
+	}
+	_, _, _, err := pagination.Fetch(&multiRangeLargeDataFetcher{}, &pagination.Setting{Limit: 10, Page: 5, Hooks: hooks})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if want := []string{"ranges 20+70 rows=63"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AfterFetch() calls = %q, want %q", got, want)
+	}
+}
@@ -1,7 +1,7 @@
 package pagination
 
 import (
-	"fmt"
+	"context"
 	"math"
 	"strconv"
 )
@@ -14,17 +14,46 @@ type Setting struct {
 	Page   int `json:"page"`
 	Cond   interface{}
 	Orders []*Order
+	// record offset of the active page (0〜). Page is ignored if Offset is not zero.
+	// When Offset is not a multiple of Limit, the first page holds the rows before
+	// the remainder, and the other pages are aligned to Offset.
+	Offset int `json:"offset"`
+	// Hooks are called around Count and FetchPage of the fetcher.
+	Hooks *Hooks `json:"-"`
+	// Metrics collects metrics of the fetch.
+	Metrics MetricsCollector `json:"-"`
+	// OutOfRange decides how to handle the page beyond the last page.
+	OutOfRange OutOfRangePolicy `json:"-"`
 }
 
+// OutOfRangePolicy decides how to handle the page beyond the last page.
+type OutOfRangePolicy int
+
+const (
+	// OutOfRangeFail returns *OutOfRangeError.
+	OutOfRangeFail OutOfRangePolicy = iota
+	// OutOfRangeClamp returns the last page as the active page.
+	OutOfRangeClamp
+	// OutOfRangeEmpty returns an empty active page with the first and the last pages.
+	OutOfRangeEmpty
+)
+
 // Pager has pagination parameters
 type Pager struct {
 	limit           int
 	page            int
 	sidePagingCount int
 	totalCount      int
+	totalLowerBound bool
+	inSnapshot      bool // set while the pages are fetched in a snapshot of TxPageFetcher
+	shift           int  // rows the first page lacks when the active page starts at an unaligned offset
 	Condition       interface{}
 	Orders          []*Order
 	fetcher         PageFetcher
+	hooks           *Hooks
+	metrics         MetricsCollector
+	outOfRange      OutOfRangePolicy
+	ctx             context.Context
 }
 
 // PageFetcher is the interface to fetch the desired range of record.
@@ -58,16 +87,14 @@ func GetPageName(i int) string {
 
 // Fetch returns paging response using arbitrary record fetcher.
 func Fetch(fetcher PageFetcher, setting *Setting) (totalCount, pageCount int, res *PagingResponse, err error) {
-	pager, err := newPager(fetcher, setting)
-	if err != nil {
-		return 0, 0, nil, err
-	}
-	res, err = pager.GetPages()
-	if err != nil {
-		return 0, 0, nil, err
-	}
+	return FetchContext(context.Background(), fetcher, setting)
+}
 
-	return pager.totalCount, pager.GetPageCount(), res, nil
+// NewPager returns a pager of the setting without a fetcher,
+// which computes ranges of pages once the total count is set with SetTotal.
+// Fetcher related fields of the setting are ignored, and GetPages fails.
+func NewPager(setting *Setting) (*Pager, error) {
+	return newPager(nil, setting)
 }
 
 func newPager(fetcher PageFetcher, setting *Setting) (*Pager, error) {
@@ -76,21 +103,36 @@ func newPager(fetcher PageFetcher, setting *Setting) (*Pager, error) {
 	pager.fetcher = fetcher
 
 	if setting.Limit != 0 {
+		if setting.Limit < 0 {
+			return nil, ErrInvalidLimit
+		}
 		pager.limit = setting.Limit
 	}
 
 	if setting.Page != 0 {
 		if setting.Page < 1 {
-			return nil, fmt.Errorf("page must be >= 1")
+			return nil, ErrInvalidPage
 		}
 		pager.page = setting.Page
 	}
 
+	if setting.Offset != 0 {
+		if setting.Offset < 0 {
+			return nil, ErrInvalidOffset
+		}
+		pager.shift = (pager.limit - setting.Offset%pager.limit) % pager.limit
+		pager.page = (setting.Offset+pager.shift)/pager.limit + 1
+	}
+
 	// currently side pages count is fixed to 2
 	pager.sidePagingCount = 2
 
 	pager.Condition = setting.Cond
 	pager.Orders = setting.Orders
+	pager.hooks = setting.Hooks
+	pager.metrics = setting.Metrics
+	pager.outOfRange = setting.OutOfRange
+	pager.ctx = context.Background()
 
 	return &pager, nil
 }
@@ -131,22 +173,28 @@ func (p *Pager) LastPageIndex() int {
 	}
 
 	// calculate the last page index
-	lastPageIndex := (p.totalCount - 1) / p.limit
+	lastPageIndex := (p.totalCount + p.shift - 1) / p.limit
 	return lastPageIndex
 }
 
 // GetActiveAndSidesLimit gets records count and offset of pages chunk.
 func (p *Pager) GetActiveAndSidesLimit() (limit, offset int) {
 	// start record index of side pages chunk
-	offset = p.StartPageIndex() * p.limit
+	offset = p.StartPageIndex()*p.limit - p.shift
+
+	// data record limit of side pages chunk
+	limit = ((p.sidePagingCount * 2) + 1) * p.limit
+
+	// the first page lacks shifted rows
+	if offset < 0 {
+		limit += offset
+		offset = 0
+	}
 
 	if offset > p.totalCount {
 		offset = p.totalCount - 1
 	}
 
-	// data record limit of side pages chunk
-	limit = ((p.sidePagingCount * 2) + 1) * p.limit
-
 	if limit > p.totalCount {
 		limit = p.totalCount
 	}
@@ -154,74 +202,156 @@ func (p *Pager) GetActiveAndSidesLimit() (limit, offset int) {
 	return limit, offset
 }
 
+// pageRange returns records count and offset of the page.
+func (p *Pager) pageRange(pageIndex int) (limit, offset int) {
+	limit = p.limit
+	offset = pageIndex*p.limit - p.shift
+	if offset < 0 {
+		limit += offset
+		offset = 0
+	}
+	return limit, offset
+}
+
 // GetPages gets formated paging response.
 func (p *Pager) GetPages() (*PagingResponse, error) {
+	if p.fetcher == nil {
+		return nil, ErrNoFetcher
+	}
+	if p.metrics != nil {
+		p.metrics.ObserveRequest(p.limit, p.page)
+	}
 
-	count, err := p.fetcher.Count(p.Condition)
-	if err != nil {
-		return nil, err
+	if fetcher, ok := asTxPageFetcher(p.fetcher); ok {
+		return p.getPagesInTx(fetcher)
+	}
+	return p.getPages()
+}
+
+func (p *Pager) getPages() (*PagingResponse, error) {
+	// CountingPageFetcher counts records while fetching the chunk
+	var counted PageFetchResult
+	countedOffset := 0
+	if fetcher, ok := asCountingPageFetcher(p.fetcher); ok {
+		var err error
+		counted, countedOffset, err = p.fetchWithCount(fetcher)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		count, err := p.count()
+		if err != nil {
+			return nil, err
+		}
+		p.totalCount = count
 	}
-	p.totalCount = count
 
 	pageCount := p.GetPageCount()
 	if pageCount == 0 {
 		return p.formatResponse(PageFetchResult{}, PageFetchResult{}, PageFetchResult{}), nil
 	}
 	if p.page > pageCount {
-		return nil, fmt.Errorf("page is out of range. page range is 1-%v", pageCount)
+		switch p.outOfRange {
+		case OutOfRangeClamp:
+			p.page = pageCount
+		case OutOfRangeEmpty:
+			return p.getFirstAndLast()
+		default:
+			return nil, &OutOfRangeError{Page: p.page, PageCount: pageCount}
+		}
 	}
 
 	// active と sides に相当する範囲をまとめて取得する
 	limit, offset := p.GetActiveAndSidesLimit()
 	activeAndSides := make(PageFetchResult, 0, limit)
-	fetchActiveInput := &PageFetchInput{
-		Limit:  limit,
-		Offset: offset,
-		Orders: p.Orders,
-	}
-	err = p.fetcher.FetchPage(p.Condition, fetchActiveInput, &activeAndSides)
-	if err != nil {
-		return nil, err
+	fetches := []pageFetch{}
+	if counted != nil && countedOffset == offset {
+		// the counted chunk starts at the same record, and has enough records
+		if len(counted) > limit {
+			counted = counted[:limit]
+		}
+		activeAndSides = counted
+	} else {
+		fetchActiveInput := &PageFetchInput{
+			Limit:  limit,
+			Offset: offset,
+			Orders: p.Orders,
+		}
+		fetches = append(fetches, pageFetch{StageActive, fetchActiveInput, &activeAndSides})
 	}
 
 	// 最初のページが範囲外の場合は取得する
 	first := make(PageFetchResult, 0, p.limit)
 	if p.StartPageIndex() > 0 {
+		firstLimit, firstOffset := p.pageRange(0)
 		fetchFirstInput := &PageFetchInput{
-			Limit:  p.limit,
-			Offset: 0,
+			Limit:  firstLimit,
+			Offset: firstOffset,
 			Orders: p.Orders,
 		}
-		err = p.fetcher.FetchPage(p.Condition, fetchFirstInput, &first)
-		if err != nil {
-			return nil, err
-		}
+		fetches = append(fetches, pageFetch{StageFirst, fetchFirstInput, &first})
 	}
 
 	// 最後のページが範囲外の場合は取得する
 	last := make(PageFetchResult, 0, p.limit)
 	if p.StartPageIndex()+(p.sidePagingCount*2) < p.LastPageIndex() {
-
+		lastLimit, lastOffset := p.pageRange(p.LastPageIndex())
 		fetchLastInput := &PageFetchInput{
-			Limit:  p.limit,
-			Offset: p.LastPageIndex() * p.limit,
+			Limit:  lastLimit,
+			Offset: lastOffset,
 			Orders: p.Orders,
 		}
-		err = p.fetcher.FetchPage(p.Condition, fetchLastInput, &last)
-		if err != nil {
-			return nil, err
-		}
+		fetches = append(fetches, pageFetch{StageLast, fetchLastInput, &last})
+	}
+
+	err := p.fetchPages(fetches)
+	if err != nil {
+		return nil, err
 	}
 
 	return p.formatResponse(first, activeAndSides, last), nil
 }
 
+// getFirstAndLast gets paging response which has only the first and the last pages.
+func (p *Pager) getFirstAndLast() (*PagingResponse, error) {
+	firstLimit, firstOffset := p.pageRange(0)
+	first := make(PageFetchResult, 0, firstLimit)
+	fetchFirstInput := &PageFetchInput{
+		Limit:  firstLimit,
+		Offset: firstOffset,
+		Orders: p.Orders,
+	}
+	fetches := []pageFetch{{StageFirst, fetchFirstInput, &first}}
+
+	var last PageFetchResult
+	if p.LastPageIndex() > 0 {
+		lastLimit, lastOffset := p.pageRange(p.LastPageIndex())
+		last = make(PageFetchResult, 0, lastLimit)
+		fetchLastInput := &PageFetchInput{
+			Limit:  lastLimit,
+			Offset: lastOffset,
+			Orders: p.Orders,
+		}
+		fetches = append(fetches, pageFetch{StageLast, fetchLastInput, &last})
+	}
+
+	err := p.fetchPages(fetches)
+	if err != nil {
+		return nil, err
+	}
+	if p.LastPageIndex() == 0 {
+		last = first
+	}
+
+	return p.formatResponse(first, PageFetchResult{}, last), nil
+}
+
 // GetPageCount はページの総数を返します
 func (p *Pager) GetPageCount() int {
-	if p.limit == 0 {
+	if p.limit == 0 || p.totalCount == 0 {
 		return 0
 	}
-	count := math.Ceil(float64(p.totalCount) / float64(p.limit))
+	count := math.Ceil(float64(p.totalCount+p.shift) / float64(p.limit))
 	return int(count)
 }
 
@@ -234,6 +364,10 @@ type Pages map[string]PageFetchResult
 // PagingResponse is a response of pager.
 type PagingResponse struct {
 	Pages Pages `json:"pages"`
+	// ActivePage is the effective active page number (1〜), which differs from the requested page when clamped.
+	ActivePage int `json:"-"`
+	// TotalLowerBound reports that the total count is a lower bound, given by LowerBoundPageFetcher.
+	TotalLowerBound bool `json:"-"`
 }
 
 func (p *Pager) formatResponse(first PageFetchResult, activeAndSides PageFetchResult, last PageFetchResult) *PagingResponse {
@@ -243,6 +377,12 @@ func (p *Pager) formatResponse(first PageFetchResult, activeAndSides PageFetchRe
 
 	page := p.StartPageIndex() + 1
 	pageIndex := 0
+
+	// the chunk starting from the first page lacks shifted rows
+	lead := 0
+	if p.StartPageIndex() == 0 {
+		lead = p.shift
+	}
 	for i, item := range activeAndSides {
 
 		// fill the active page data
@@ -264,7 +404,7 @@ func (p *Pager) formatResponse(first PageFetchResult, activeAndSides PageFetchRe
 		}
 
 		// ページの区切り
-		if (i+1)%p.limit == 0 {
+		if (i+1+lead)%p.limit == 0 {
 			page++
 			if pageIndex < sidesLen && len(sides[pageIndex]) > 0 {
 				pageIndex++
@@ -284,6 +424,8 @@ func (p *Pager) formatResponse(first PageFetchResult, activeAndSides PageFetchRe
 	}
 
 	return &PagingResponse{
-		Pages: responsePage,
+		Pages:           responsePage,
+		ActivePage:      p.page,
+		TotalLowerBound: p.totalLowerBound,
 	}
 }
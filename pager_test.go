@@ -1,10 +1,12 @@
 package pagination_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
 	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/paginationtest"
 )
 
 func TestPager_GetActiveAndSidesLimit(t *testing.T) {
@@ -337,7 +339,7 @@ func TestPager_GetPages_LargeData_Last(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			fetcher := newLargeDataFetcher()
-			p, err := pagination.NewPager(fetcher, &pagination.Setting{
+			p, err := pagination.NewFetchingPager(fetcher, &pagination.Setting{
 				Limit:  tt.args.Limit,
 				Page:   tt.args.Page,
 				Cond:   tt.args.Condition,
@@ -365,3 +367,171 @@ func TestPager_GetPages_LargeData_Last(t *testing.T) {
 		})
 	}
 }
+
+func TestFetch_Offset(t *testing.T) {
+	tests := []struct {
+		name           string
+		setting        *pagination.Setting
+		wantTotalCount int
+		wantPageCount  int
+		wantPages      map[string][]int
+		wantErr        bool
+	}{
+		{"aligned offset is same as page", &pagination.Setting{Limit: 2, Offset: 4}, 11, 6, map[string][]int{
+			"first":          {0, 1},
+			"before_distant": {0, 1},
+			"before_near":    {2, 3},
+			"active":         {4, 5},
+			"after_near":     {6, 7},
+			"after_distant":  {8, 9},
+			"last":           {10},
+		}, false},
+		{"unaligned offset shifts pages", &pagination.Setting{Limit: 2, Offset: 3}, 11, 6, map[string][]int{
+			"first":          {0},
+			"before_distant": {0},
+			"before_near":    {1, 2},
+			"active":         {3, 4},
+			"after_near":     {5, 6},
+			"after_distant":  {7, 8},
+			"last":           {9, 10},
+		}, false},
+		{"unaligned offset far from the first page", &pagination.Setting{Limit: 2, Offset: 9}, 11, 6, map[string][]int{
+			"first":          {0},
+			"before_distant": {1, 2},
+			"before_near":    {3, 4},
+			"active":         {9, 10},
+			"after_near":     {5, 6},
+			"after_distant":  {7, 8},
+			"last":           {9, 10},
+		}, false},
+		{"offset overrides page", &pagination.Setting{Limit: 5, Page: 1, Offset: 7}, 11, 3, map[string][]int{
+			"first":          {0, 1},
+			"before_distant": {0, 1},
+			"before_near":    {2, 3, 4, 5, 6},
+			"active":         {7, 8, 9, 10},
+			"last":           {7, 8, 9, 10},
+		}, false},
+		{"offset is out of range", &pagination.Setting{Limit: 2, Offset: 11}, 0, 0, nil, true},
+		{"negative offset", &pagination.Setting{Limit: 2, Offset: -1}, 0, 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTotalCount, gotPageCount, gotRes, err := pagination.Fetch(newFruitFetcher(), tt.setting)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if gotTotalCount != tt.wantTotalCount {
+				t.Errorf("Fetch() gotTotalCount = %v, want %v", gotTotalCount, tt.wantTotalCount)
+			}
+			if gotPageCount != tt.wantPageCount {
+				t.Errorf("Fetch() gotPageCount = %v, want %v", gotPageCount, tt.wantPageCount)
+			}
+			for key, indexes := range tt.wantPages {
+				want := pagination.PageFetchResult{}
+				for _, i := range indexes {
+					want = append(want, dummyFruits[i])
+				}
+				if !reflect.DeepEqual(gotRes.Pages[key], want) {
+					t.Errorf("Fetch() gotRes.Pages[%v] = %v, want %v", key, gotRes.Pages[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestFetch_OutOfRange(t *testing.T) {
+	tests := []struct {
+		name           string
+		setting        *pagination.Setting
+		wantActivePage int
+		wantPages      map[string][]int
+		wantErr        bool
+	}{
+		{"fail", &pagination.Setting{Limit: 2, Page: 7, OutOfRange: pagination.OutOfRangeFail}, 0, nil, true},
+		{"clamp", &pagination.Setting{Limit: 2, Page: 7, OutOfRange: pagination.OutOfRangeClamp}, 6, map[string][]int{
+			"first":          {0, 1},
+			"before_distant": {2, 3},
+			"before_near":    {4, 5},
+			"after_near":     {6, 7},
+			"after_distant":  {8, 9},
+			"active":         {10},
+			"last":           {10},
+		}, false},
+		{"clamp in range", &pagination.Setting{Limit: 2, Page: 2, OutOfRange: pagination.OutOfRangeClamp}, 2, map[string][]int{
+			"active": {2, 3},
+		}, false},
+		{"empty", &pagination.Setting{Limit: 2, Page: 7, OutOfRange: pagination.OutOfRangeEmpty}, 7, map[string][]int{
+			"first":          {0, 1},
+			"before_distant": nil,
+			"before_near":    nil,
+			"after_near":     nil,
+			"after_distant":  nil,
+			"active":         {},
+			"last":           {10},
+		}, false},
+		{"empty with single page", &pagination.Setting{Limit: 20, Page: 3, OutOfRange: pagination.OutOfRangeEmpty}, 3, map[string][]int{
+			"first":  {0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			"active": {},
+			"last":   {0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTotalCount, gotPageCount, gotRes, err := pagination.Fetch(newFruitFetcher(), tt.setting)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if gotTotalCount != 11 {
+				t.Errorf("Fetch() gotTotalCount = %v, want 11", gotTotalCount)
+			}
+			if wantPageCount := (11 + tt.setting.Limit - 1) / tt.setting.Limit; gotPageCount != wantPageCount {
+				t.Errorf("Fetch() gotPageCount = %v, want %v", gotPageCount, wantPageCount)
+			}
+			if gotRes.ActivePage != tt.wantActivePage {
+				t.Errorf("Fetch() gotRes.ActivePage = %v, want %v", gotRes.ActivePage, tt.wantActivePage)
+			}
+			for key, indexes := range tt.wantPages {
+				var want pagination.PageFetchResult
+				if indexes != nil {
+					want = pagination.PageFetchResult{}
+				}
+				for _, i := range indexes {
+					want = append(want, dummyFruits[i])
+				}
+				if !reflect.DeepEqual(gotRes.Pages[key], want) {
+					t.Errorf("Fetch() gotRes.Pages[%v] = %v, want %v", key, gotRes.Pages[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestFetch_OutOfRangeNegativeLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy pagination.OutOfRangePolicy
+	}{
+		{"fail", pagination.OutOfRangeFail},
+		{"clamp", pagination.OutOfRangeClamp},
+		{"empty", pagination.OutOfRangeEmpty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := paginationtest.NewFetcher(1, 2, 3, 4, 5)
+			_, _, _, err := pagination.Fetch(fetcher, &pagination.Setting{Limit: -2, Page: 3, OutOfRange: tt.policy})
+			if !errors.Is(err, pagination.ErrInvalidLimit) {
+				t.Errorf("Fetch() error = %v, want %v", err, pagination.ErrInvalidLimit)
+			}
+			// rejected before any call to the fetcher
+			fetcher.AssertCalls(t)
+		})
+	}
+}
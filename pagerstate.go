@@ -0,0 +1,81 @@
+package pagination
+
+// SetTotal sets the total count of records, which decides the page count and ranges of pages.
+func (p *Pager) SetTotal(totalCount int) {
+	p.totalCount = totalCount
+}
+
+// Limit returns the record count per page.
+func (p *Pager) Limit() int {
+	return p.limit
+}
+
+// Page returns the active page number (1〜).
+func (p *Pager) Page() int {
+	return p.page
+}
+
+// TotalCount returns the total count of records.
+func (p *Pager) TotalCount() int {
+	return p.totalCount
+}
+
+// Offset returns the record offset of the active page.
+func (p *Pager) Offset() int {
+	_, offset := p.pageRange(p.ActivePageIndex())
+	return offset
+}
+
+// HasNext reports whether the active page has a next page.
+func (p *Pager) HasNext() bool {
+	return p.page < p.GetPageCount()
+}
+
+// HasPrev reports whether the active page has a previous page.
+func (p *Pager) HasPrev() bool {
+	return p.page > 1 && p.GetPageCount() > 0
+}
+
+// PageRange returns records count and offset of the named page of PagingResponse,
+// which is "active", "first", "last" or a name of GetPageName.
+// ok is false if the response has no such page. The count is limited to the total count.
+func (p *Pager) PageRange(name string) (limit, offset int, ok bool) {
+	if p.GetPageCount() == 0 || p.page > p.GetPageCount() {
+		return 0, 0, false
+	}
+
+	pageIndex := -1
+	switch name {
+	case "active":
+		pageIndex = p.ActivePageIndex()
+	case "first":
+		pageIndex = 0
+	case "last":
+		pageIndex = p.LastPageIndex()
+	default:
+		// side pages are named in order except the active page, like formatResponse
+		sideIndex := 0
+		end := p.StartPageIndex() + p.sidePagingCount*2
+		if end > p.LastPageIndex() {
+			end = p.LastPageIndex()
+		}
+		for i := p.StartPageIndex(); i <= end; i++ {
+			if i == p.ActivePageIndex() {
+				continue
+			}
+			if GetPageName(sideIndex) == name {
+				pageIndex = i
+			}
+			sideIndex++
+		}
+	}
+	if pageIndex < 0 {
+		return 0, 0, false
+	}
+
+	limit, offset = p.pageRange(pageIndex)
+	if rest := p.totalCount - offset; rest < limit {
+		limit = rest
+	}
+	return limit, offset, true
+}
@@ -0,0 +1,139 @@
+package pagination_test
+
+import (
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+func TestNewPager_PageRange(t *testing.T) {
+	type pageRange struct {
+		limit, offset int
+		ok            bool
+	}
+	tests := []struct {
+		name       string
+		setting    pagination.Setting
+		totalCount int
+		want       map[string]pageRange
+	}{
+		{
+			name:       "middle",
+			setting:    pagination.Setting{Limit: 10, Page: 3},
+			totalCount: 95,
+			want: map[string]pageRange{
+				"active":         {10, 20, true},
+				"first":          {10, 0, true},
+				"last":           {5, 90, true},
+				"before_distant": {10, 0, true},
+				"before_near":    {10, 10, true},
+				"after_near":     {10, 30, true},
+				"after_distant":  {10, 40, true},
+				"unknown":        {0, 0, false},
+			},
+		},
+		{
+			name:       "last page",
+			setting:    pagination.Setting{Limit: 10, Page: 10},
+			totalCount: 95,
+			want: map[string]pageRange{
+				"active":         {5, 90, true},
+				"before_distant": {10, 50, true},
+				"after_distant":  {10, 80, true},
+			},
+		},
+		{
+			name:       "offset",
+			setting:    pagination.Setting{Limit: 10, Offset: 25},
+			totalCount: 95,
+			want: map[string]pageRange{
+				"active": {10, 25, true},
+				"first":  {5, 0, true},
+				"last":   {10, 85, true},
+			},
+		},
+		{
+			name:       "out of range",
+			setting:    pagination.Setting{Limit: 10, Page: 20},
+			totalCount: 95,
+			want: map[string]pageRange{
+				"active": {0, 0, false},
+			},
+		},
+		{
+			name:       "empty",
+			setting:    pagination.Setting{Limit: 10, Page: 1},
+			totalCount: 0,
+			want: map[string]pageRange{
+				"active": {0, 0, false},
+				"first":  {0, 0, false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := pagination.NewPager(&tt.setting)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.SetTotal(tt.totalCount)
+			for name, want := range tt.want {
+				limit, offset, ok := p.PageRange(name)
+				got := pageRange{limit, offset, ok}
+				if got != want {
+					t.Errorf("PageRange(%q) = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPager_State(t *testing.T) {
+	tests := []struct {
+		name             string
+		setting          pagination.Setting
+		totalCount       int
+		offset           int
+		hasNext, hasPrev bool
+	}{
+		{"first", pagination.Setting{Limit: 10, Page: 1}, 95, 0, true, false},
+		{"middle", pagination.Setting{Limit: 10, Page: 3}, 95, 20, true, true},
+		{"last", pagination.Setting{Limit: 10, Page: 10}, 95, 90, false, true},
+		{"offset", pagination.Setting{Limit: 10, Offset: 25}, 95, 25, true, true},
+		{"empty", pagination.Setting{Limit: 10, Page: 2}, 0, 10, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := pagination.NewPager(&tt.setting)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.SetTotal(tt.totalCount)
+			if got := p.Offset(); got != tt.offset {
+				t.Errorf("Offset() = %d, want %d", got, tt.offset)
+			}
+			if got := p.HasNext(); got != tt.hasNext {
+				t.Errorf("HasNext() = %v, want %v", got, tt.hasNext)
+			}
+			if got := p.HasPrev(); got != tt.hasPrev {
+				t.Errorf("HasPrev() = %v, want %v", got, tt.hasPrev)
+			}
+		})
+	}
+}
+
+func TestNewPager_InvalidSetting(t *testing.T) {
+	if _, err := pagination.NewPager(&pagination.Setting{Page: -1}); err != pagination.ErrInvalidPage {
+		t.Errorf("err = %v, want %v", err, pagination.ErrInvalidPage)
+	}
+}
+
+func TestNewPager_GetPages(t *testing.T) {
+	p, err := pagination.NewPager(&pagination.Setting{Limit: 10, Page: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.GetPages(); err != pagination.ErrNoFetcher {
+		t.Errorf("err = %v, want %v", err, pagination.ErrNoFetcher)
+	}
+}
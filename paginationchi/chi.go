@@ -0,0 +1,58 @@
+// Package paginationchi provides pagination helpers for chi and other net/http routers.
+package paginationchi
+
+import (
+	"context"
+	"net/http"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// parserKey is the key of the parser of Middleware in the request context.
+type parserKey struct{}
+
+// Middleware parses pagination parameters of the URL query of requests with the parser, and stores them in the request context
+// with pagination.WithQuery.
+// The request body is not read here, so routes without pagination are not affected. Query parses it on POST requests.
+// pagination.DefaultParser is used if parser is nil.
+func Middleware(parser *pagination.Parser) func(http.Handler) http.Handler {
+	if parser == nil {
+		parser = pagination.DefaultParser
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := parser.ParseValues(r.URL.Query())
+			ctx := context.WithValue(pagination.WithQuery(r.Context(), q), parserKey{}, parser)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Query returns the pagination parameters stored by Middleware.
+// A JSON body of a POST request is parsed here with the parser of Middleware, which takes precedence over the URL query.
+// The request is parsed with pagination.DefaultParser if Middleware is not used.
+func Query(r *http.Request) (*pagination.Query, error) {
+	if q, ok := pagination.QueryFrom(r.Context()); ok && r.Method != http.MethodPost {
+		return q, nil
+	}
+	if parser, ok := r.Context().Value(parserKey{}).(*pagination.Parser); ok {
+		return parser.ParseRequest(r)
+	}
+	return pagination.ParseRequest(r)
+}
+
+// Paginate fetches pages of the request with the fetcher, and writes them as JSON with X-Total-Count and X-Total-Pages headers.
+// Errors are written as JSON messages with the status code of pagination.HTTPStatus, and returned.
+func Paginate(w http.ResponseWriter, r *http.Request, fetcher pagination.PageFetcher, cond interface{}) error {
+	q, err := Query(r)
+	if err != nil {
+		pagination.WriteError(w, err)
+		return err
+	}
+	totalCount, pageCount, res, err := pagination.FetchContext(r.Context(), fetcher, q.Setting(cond))
+	if err != nil {
+		pagination.WriteError(w, err)
+		return err
+	}
+	return pagination.WriteResponse(w, totalCount, pageCount, res)
+}
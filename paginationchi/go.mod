@@ -0,0 +1,9 @@
+module github.com/gemcook/pagination-go/paginationchi
+
+go 1.23
+
+require github.com/gemcook/pagination-go v0.0.0
+
+require github.com/go-chi/chi/v5 v5.3.2
+
+replace github.com/gemcook/pagination-go => ../
@@ -0,0 +1,72 @@
+// Package paginationecho provides pagination helpers for Echo.
+package paginationecho
+
+import (
+	"net/http"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// contextKey is the key of the pagination parameters in echo.Context.
+	contextKey = "github.com/gemcook/pagination-go/paginationecho.query"
+	// parserKey is the key of the parser of Middleware in echo.Context.
+	parserKey = "github.com/gemcook/pagination-go/paginationecho.parser"
+)
+
+// Middleware parses pagination parameters of the URL query of requests with the parser, and stores them in echo.Context
+// and in the request context with pagination.WithQuery.
+// The request body is not read here, so routes without pagination are not affected. Query parses it on POST requests.
+// pagination.DefaultParser is used if parser is nil.
+func Middleware(parser *pagination.Parser) echo.MiddlewareFunc {
+	if parser == nil {
+		parser = pagination.DefaultParser
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			q := parser.ParseValues(c.Request().URL.Query())
+			c.Set(parserKey, parser)
+			c.Set(contextKey, q)
+			c.SetRequest(c.Request().WithContext(pagination.WithQuery(c.Request().Context(), q)))
+			return next(c)
+		}
+	}
+}
+
+// Query returns the pagination parameters stored by Middleware.
+// A JSON body of a POST request is parsed here with the parser of Middleware, and the parameters are stored in place of the ones of the URL query.
+// The request is parsed with pagination.DefaultParser if Middleware is not used.
+func Query(c echo.Context) (*pagination.Query, error) {
+	if q, ok := c.Get(contextKey).(*pagination.Query); ok && c.Request().Method != http.MethodPost {
+		return q, nil
+	}
+	parser, ok := c.Get(parserKey).(*pagination.Parser)
+	if !ok {
+		return pagination.ParseRequest(c.Request())
+	}
+	q, err := parser.ParseRequest(c.Request())
+	if err != nil {
+		return nil, err
+	}
+	c.Set(contextKey, q)
+	c.SetRequest(c.Request().WithContext(pagination.WithQuery(c.Request().Context(), q)))
+	return q, nil
+}
+
+// Paginate fetches pages of the request with the fetcher, and writes them as JSON with X-Total-Count and X-Total-Pages headers.
+// Errors are written as JSON messages with the status code of pagination.HTTPStatus, and returned.
+// The response is committed then, so the HTTPErrorHandler of Echo only logs them.
+func Paginate(c echo.Context, fetcher pagination.PageFetcher, cond interface{}) error {
+	q, err := Query(c)
+	if err != nil {
+		pagination.WriteError(c.Response(), err)
+		return err
+	}
+	totalCount, pageCount, res, err := pagination.FetchContext(c.Request().Context(), fetcher, q.Setting(cond))
+	if err != nil {
+		pagination.WriteError(c.Response(), err)
+		return err
+	}
+	return pagination.WriteResponse(c.Response(), totalCount, pageCount, res)
+}
@@ -0,0 +1,70 @@
+// Package paginationgin provides pagination helpers for Gin.
+package paginationgin
+
+import (
+	"net/http"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// contextKey is the key of the pagination parameters in gin.Context.
+	contextKey = "github.com/gemcook/pagination-go/paginationgin.query"
+	// parserKey is the key of the parser of Middleware in gin.Context.
+	parserKey = "github.com/gemcook/pagination-go/paginationgin.parser"
+)
+
+// Middleware parses pagination parameters of the URL query of requests with the parser, and stores them in gin.Context
+// and in the request context with pagination.WithQuery.
+// The request body is not read here, so routes without pagination are not affected. Query parses it on POST requests.
+// pagination.DefaultParser is used if parser is nil.
+func Middleware(parser *pagination.Parser) gin.HandlerFunc {
+	if parser == nil {
+		parser = pagination.DefaultParser
+	}
+	return func(c *gin.Context) {
+		q := parser.ParseValues(c.Request.URL.Query())
+		c.Set(parserKey, parser)
+		c.Set(contextKey, q)
+		c.Request = c.Request.WithContext(pagination.WithQuery(c.Request.Context(), q))
+		c.Next()
+	}
+}
+
+// Query returns the pagination parameters stored by Middleware.
+// A JSON body of a POST request is parsed here with the parser of Middleware, and the parameters are stored in place of the ones of the URL query.
+// The request is parsed with pagination.DefaultParser if Middleware is not used.
+func Query(c *gin.Context) (*pagination.Query, error) {
+	v, ok := c.Get(contextKey)
+	if ok && c.Request.Method != http.MethodPost {
+		return v.(*pagination.Query), nil
+	}
+	parser, ok := c.Get(parserKey)
+	if !ok {
+		return pagination.ParseRequest(c.Request)
+	}
+	q, err := parser.(*pagination.Parser).ParseRequest(c.Request)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(contextKey, q)
+	c.Request = c.Request.WithContext(pagination.WithQuery(c.Request.Context(), q))
+	return q, nil
+}
+
+// Paginate fetches pages of the request with the fetcher, and writes them as JSON with X-Total-Count and X-Total-Pages headers.
+// Errors are written as JSON messages with the status code of pagination.HTTPStatus, and returned.
+func Paginate(c *gin.Context, fetcher pagination.PageFetcher, cond interface{}) error {
+	q, err := Query(c)
+	if err != nil {
+		pagination.WriteError(c.Writer, err)
+		return err
+	}
+	totalCount, pageCount, res, err := pagination.FetchContext(c.Request.Context(), fetcher, q.Setting(cond))
+	if err != nil {
+		pagination.WriteError(c.Writer, err)
+		return err
+	}
+	return pagination.WriteResponse(c.Writer, totalCount, pageCount, res)
+}
@@ -0,0 +1,143 @@
+package paginationgin_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/paginationgin"
+	"github.com/gin-gonic/gin"
+)
+
+type fruitFetcher struct {
+	fruits []string
+	err    error
+}
+
+func (ff *fruitFetcher) Count(cond interface{}) (int, error) {
+	return len(ff.fruits), ff.err
+}
+
+func (ff *fruitFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	for i := input.Offset; i < input.Offset+input.Limit && i < len(ff.fruits); i++ {
+		*result = append(*result, ff.fruits[i])
+	}
+	return nil
+}
+
+var dummyFruits = []string{"Apple", "Pear", "Banana", "Orange", "Kiwi"}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newRouter(fetcher pagination.PageFetcher, parser *pagination.Parser) http.Handler {
+	r := gin.New()
+	r.Use(paginationgin.Middleware(parser))
+	r.GET("/fruits", func(c *gin.Context) {
+		paginationgin.Paginate(c, fetcher, nil)
+	})
+	r.POST("/fruits/search", func(c *gin.Context) {
+		paginationgin.Paginate(c, fetcher, nil)
+	})
+	r.POST("/orders", func(c *gin.Context) {
+		b, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusCreated, "application/json", b)
+	})
+	return r
+}
+
+func TestPaginate(t *testing.T) {
+	parser := pagination.NewParser()
+	parser.Limit.Aliases = []string{"per_page"}
+
+	tests := []struct {
+		name        string
+		fetcher     pagination.PageFetcher
+		target      string
+		body        string
+		wantStatus  int
+		wantTotal   string
+		wantBodyHas string
+	}{
+		{"ok", &fruitFetcher{fruits: dummyFruits}, "/fruits?per_page=2&page=2", "", 200, "5", `"active":["Banana","Orange"]`},
+		{"json body", &fruitFetcher{fruits: dummyFruits}, "/fruits/search", `{"limit": 3}`, 200, "5", `"active":["Apple","Pear","Banana"]`},
+		{"invalid page", &fruitFetcher{fruits: dummyFruits}, "/fruits?page=-1", "", 400, "", `{"message":"page must be >= 1"}`},
+		{"out of range", &fruitFetcher{fruits: dummyFruits}, "/fruits?limit=2&page=4", "", 404, "", `{"message":"page is out of range. page range is 1-3"}`},
+		{"fetcher error", &fruitFetcher{err: errors.New("db is down")}, "/fruits", "", 500, "", `{"message":"Internal Server Error"}`},
+		{"invalid body", &fruitFetcher{fruits: dummyFruits}, "/fruits/search", `{"limit": `, 400, "", `"message":"invalid JSON body`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := http.MethodGet
+			if tt.body != "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+			newRouter(tt.fetcher, parser).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("X-Total-Count"); got != tt.wantTotal {
+				t.Errorf("X-Total-Count = %q, want %q", got, tt.wantTotal)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBodyHas) {
+				t.Errorf("body = %s, want %s", w.Body.String(), tt.wantBodyHas)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	parser := pagination.NewParser()
+	parser.DefaultLimit = 25
+
+	var withMiddleware *pagination.Query
+	r := gin.New()
+	r.Use(paginationgin.Middleware(parser))
+	var fromContext *pagination.Query
+	r.GET("/fruits", func(c *gin.Context) {
+		withMiddleware, _ = paginationgin.Query(c)
+		fromContext, _ = pagination.QueryFrom(c.Request.Context())
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fruits", nil))
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/fruits", nil)
+	withoutMiddleware, _ := paginationgin.Query(c)
+
+	if withMiddleware.Limit != 25 {
+		t.Errorf("Query() with middleware limit = %v, want 25", withMiddleware.Limit)
+	}
+	if fromContext != withMiddleware {
+		t.Errorf("QueryFrom() = %v, want %v", fromContext, withMiddleware)
+	}
+	if withoutMiddleware.Limit != 10 {
+		t.Errorf("Query() without middleware limit = %v, want 10", withoutMiddleware.Limit)
+	}
+}
+
+func TestMiddleware_OtherRoutes(t *testing.T) {
+	// bodies of routes without pagination are left to their handlers, even if they are not pagination parameters
+	for _, body := range []string{`[{"item": "Apple"}]`, `{"limit": `} {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newRouter(&fruitFetcher{fruits: dummyFruits}, nil).ServeHTTP(w, req)
+		if w.Code != http.StatusCreated || w.Body.String() != body {
+			t.Errorf("POST /orders %s = %v %s, want 201 %s", body, w.Code, w.Body.String(), body)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Package paginationpb has protobuf messages of pagination and conversions from/to package pagination.
+package paginationpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative pagination.proto
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pagination "github.com/gemcook/pagination-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ErrInvalidPageToken is returned when the page token can not be decoded.
+// It wraps pagination.ErrInvalidCursor, so pagination.HTTPStatus maps it to 400.
+var ErrInvalidPageToken = fmt.Errorf("%w: page token can not be decoded", pagination.ErrInvalidCursor)
+
+const pageTokenPrefix = "offset:"
+
+// EncodePageToken returns an opaque page token of the record offset.
+func EncodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(pageTokenPrefix + strconv.Itoa(offset)))
+}
+
+// DecodePageToken returns the record offset of the page token.
+func DecodePageToken(token string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(b), pageTokenPrefix) {
+		return 0, ErrInvalidPageToken
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(b), pageTokenPrefix))
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidPageToken
+	}
+	return offset, nil
+}
+
+// ToQuery converts the request message to pagination query.
+// page_size and page_token take precedence over limit and cursor.
+func ToQuery(req *PaginationRequest) (*pagination.Query, error) {
+	q := &pagination.Query{}
+	q.Init()
+	q.Sort = []*pagination.Order{}
+
+	if req.GetLimit() != 0 {
+		q.Limit = int(req.GetLimit())
+	}
+	if req.GetPageSize() != 0 {
+		q.Limit = int(req.GetPageSize())
+	}
+	if req.GetPage() != 0 {
+		q.Page = int(req.GetPage())
+	}
+	if req.GetOffset() != 0 {
+		q.Offset = int(req.GetOffset())
+	}
+
+	token := req.GetPageToken()
+	if token == "" {
+		token = req.GetCursor()
+	}
+	if token != "" {
+		offset, err := DecodePageToken(token)
+		if err != nil {
+			return nil, err
+		}
+		q.Page = 1
+		q.Offset = offset
+	}
+
+	for _, o := range req.GetSort() {
+		d := pagination.DirectionAsc
+		if o.GetDirection() == Direction_DIRECTION_DESC {
+			d = pagination.DirectionDesc
+		}
+		q.Sort = append(q.Sort, &pagination.Order{Direction: d, ColumnName: o.GetColumnName()})
+	}
+	return q, nil
+}
+
+// ToSetting converts the request message to pagination setting with the fetching condition.
+func ToSetting(req *PaginationRequest, cond interface{}) (*pagination.Setting, error) {
+	q, err := ToQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	return &pagination.Setting{
+		Limit:  q.Limit,
+		Page:   q.Page,
+		Offset: q.Offset,
+		Cond:   cond,
+		Orders: q.Sort,
+	}, nil
+}
+
+// FromQuery converts pagination query to the request message.
+func FromQuery(q *pagination.Query) *PaginationRequest {
+	req := &PaginationRequest{
+		Limit:  int32(q.Limit),
+		Page:   int32(q.Page),
+		Offset: int32(q.Offset),
+	}
+	for _, o := range q.Sort {
+		d := Direction_DIRECTION_ASC
+		if o.Direction == pagination.DirectionDesc {
+			d = Direction_DIRECTION_DESC
+		}
+		req.Sort = append(req.Sort, &Order{ColumnName: o.ColumnName, Direction: d})
+	}
+	return req
+}
+
+// ItemMarshaler converts a record of pages to Any message.
+type ItemMarshaler func(item interface{}) (*anypb.Any, error)
+
+// ItemUnmarshaler converts Any message to a record of pages.
+type ItemUnmarshaler func(item *anypb.Any) (interface{}, error)
+
+// MarshalProtoItem is the default ItemMarshaler, which accepts records of proto.Message.
+func MarshalProtoItem(item interface{}) (*anypb.Any, error) {
+	m, ok := item.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("item must be proto.Message, but got %T", item)
+	}
+	return anypb.New(m)
+}
+
+// UnmarshalProtoItem is the default ItemUnmarshaler, which returns records of proto.Message.
+func UnmarshalProtoItem(item *anypb.Any) (interface{}, error) {
+	return item.UnmarshalNew()
+}
+
+// NewResponse converts the result of pagination.Fetch with the setting to the response message.
+// MarshalProtoItem is used if marshal is nil.
+func NewResponse(totalCount, pageCount int, res *pagination.PagingResponse, setting *pagination.Setting, marshal ItemMarshaler) (*PaginationResponse, error) {
+	if marshal == nil {
+		marshal = MarshalProtoItem
+	}
+
+	pages := make(map[string]*Page, len(res.Pages))
+	for name, items := range res.Pages {
+		page := &Page{Items: make([]*anypb.Any, 0, len(items))}
+		for _, item := range items {
+			a, err := marshal(item)
+			if err != nil {
+				return nil, err
+			}
+			page.Items = append(page.Items, a)
+		}
+		pages[name] = page
+	}
+
+	page, offset, limit := activePage(setting)
+	// the active page is clamped by pagination.OutOfRangeClamp
+	if res.ActivePage != 0 {
+		page = res.ActivePage
+	}
+
+	msg := &PaginationResponse{
+		Pages:      pages,
+		TotalCount: int64(totalCount),
+		PageCount:  int32(pageCount),
+		Page:       int32(page),
+	}
+	if offset+limit < totalCount {
+		msg.NextPageToken = EncodePageToken(offset + limit)
+	}
+	return msg, nil
+}
+
+// ToPagingResponse converts the response message to pagination.PagingResponse.
+// UnmarshalProtoItem is used if unmarshal is nil.
+func ToPagingResponse(msg *PaginationResponse, unmarshal ItemUnmarshaler) (*pagination.PagingResponse, error) {
+	if unmarshal == nil {
+		unmarshal = UnmarshalProtoItem
+	}
+
+	pages := make(pagination.Pages, len(msg.GetPages()))
+	for name, page := range msg.GetPages() {
+		items := make(pagination.PageFetchResult, 0, len(page.GetItems()))
+		for _, a := range page.GetItems() {
+			item, err := unmarshal(a)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		pages[name] = items
+	}
+	return &pagination.PagingResponse{Pages: pages}, nil
+}
+
+// activePage returns the page number, record offset and limit of the active page, in the same way as pagination.Fetch.
+func activePage(setting *pagination.Setting) (page, offset, limit int) {
+	limit = 10
+	if setting.Limit != 0 {
+		limit = setting.Limit
+	}
+	page = 1
+	if setting.Page != 0 {
+		page = setting.Page
+	}
+	offset = (page - 1) * limit
+
+	if setting.Offset != 0 {
+		offset = setting.Offset
+		shift := (limit - offset%limit) % limit
+		page = (offset+shift)/limit + 1
+	}
+	return page, offset, limit
+}
@@ -0,0 +1,157 @@
+package paginationpb_test
+
+import (
+	"reflect"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/paginationpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type numberFetcher struct {
+	numbers []int64
+}
+
+func (nf *numberFetcher) Count(cond interface{}) (int, error) {
+	return len(nf.numbers), nil
+}
+
+func (nf *numberFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	toIndex := input.Offset + input.Limit
+	if toIndex > len(nf.numbers) {
+		toIndex = len(nf.numbers)
+	}
+	for _, n := range nf.numbers[input.Offset:toIndex] {
+		*result = append(*result, wrapperspb.Int64(n))
+	}
+	return nil
+}
+
+func TestPageToken(t *testing.T) {
+	for _, offset := range []int{0, 1, 40, 12345} {
+		got, err := paginationpb.DecodePageToken(paginationpb.EncodePageToken(offset))
+		if err != nil || got != offset {
+			t.Errorf("DecodePageToken(EncodePageToken(%v)) = %v, %v", offset, got, err)
+		}
+	}
+	for _, token := range []string{"!", "b2Zmc2V0Ong", "Zm9vOjQw"} {
+		if _, err := paginationpb.DecodePageToken(token); err != paginationpb.ErrInvalidPageToken {
+			t.Errorf("DecodePageToken(%q) error = %v, want %v", token, err, paginationpb.ErrInvalidPageToken)
+		}
+	}
+	if got := pagination.HTTPStatus(paginationpb.ErrInvalidPageToken); got != 400 {
+		t.Errorf("HTTPStatus(ErrInvalidPageToken) = %v, want 400", got)
+	}
+}
+
+func TestToQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *paginationpb.PaginationRequest
+		want    *pagination.Query
+		wantErr bool
+	}{
+		{"default", &paginationpb.PaginationRequest{}, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: true}, false},
+		{"limit, page and sort", &paginationpb.PaginationRequest{
+			Limit: 5,
+			Page:  3,
+			Sort: []*paginationpb.Order{
+				{ColumnName: "price", Direction: paginationpb.Direction_DIRECTION_DESC},
+				{ColumnName: "name"},
+			},
+		}, &pagination.Query{Limit: 5, Page: 3, Sort: []*pagination.Order{
+			{Direction: pagination.DirectionDesc, ColumnName: "price"},
+			{Direction: pagination.DirectionAsc, ColumnName: "name"},
+		}, Enabled: true}, false},
+		{"cursor", &paginationpb.PaginationRequest{Limit: 5, Page: 3, Cursor: paginationpb.EncodePageToken(40)}, &pagination.Query{Limit: 5, Page: 1, Sort: []*pagination.Order{}, Enabled: true, Offset: 40}, false},
+		{"AIP-158", &paginationpb.PaginationRequest{Limit: 5, PageSize: 20, Cursor: paginationpb.EncodePageToken(10), PageToken: paginationpb.EncodePageToken(60)}, &pagination.Query{Limit: 20, Page: 1, Sort: []*pagination.Order{}, Enabled: true, Offset: 60}, false},
+		{"invalid page token", &paginationpb.PaginationRequest{PageToken: "!"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := paginationpb.ToQuery(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromQuery(t *testing.T) {
+	q := &pagination.Query{Limit: 5, Page: 2, Offset: 7, Sort: []*pagination.Order{
+		{Direction: pagination.DirectionDesc, ColumnName: "price"},
+	}}
+	want := &paginationpb.PaginationRequest{Limit: 5, Page: 2, Offset: 7, Sort: []*paginationpb.Order{
+		{ColumnName: "price", Direction: paginationpb.Direction_DIRECTION_DESC},
+	}}
+	if got := paginationpb.FromQuery(q); !proto.Equal(got, want) {
+		t.Errorf("FromQuery() = %v, want %v", got, want)
+	}
+}
+
+func TestNewResponse(t *testing.T) {
+	fetcher := &numberFetcher{numbers: []int64{1, 2, 3, 4, 5, 6, 7}}
+
+	tests := []struct {
+		name          string
+		req           *paginationpb.PaginationRequest
+		wantPage      int32
+		wantActive    []int64
+		wantNextToken string
+	}{
+		{"first page", &paginationpb.PaginationRequest{Limit: 3}, 1, []int64{1, 2, 3}, paginationpb.EncodePageToken(3)},
+		{"page token", &paginationpb.PaginationRequest{PageSize: 3, PageToken: paginationpb.EncodePageToken(3)}, 2, []int64{4, 5, 6}, paginationpb.EncodePageToken(6)},
+		{"last page", &paginationpb.PaginationRequest{Limit: 3, Page: 3}, 3, []int64{7}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setting, err := paginationpb.ToSetting(tt.req, nil)
+			if err != nil {
+				t.Fatalf("ToSetting() error = %v", err)
+			}
+			totalCount, pageCount, res, err := pagination.Fetch(fetcher, setting)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+
+			got, err := paginationpb.NewResponse(totalCount, pageCount, res, setting, nil)
+			if err != nil {
+				t.Fatalf("NewResponse() error = %v", err)
+			}
+			if got.GetTotalCount() != 7 || got.GetPageCount() != 3 || got.GetPage() != tt.wantPage {
+				t.Errorf("NewResponse() totals = %v, %v, %v, want 7, 3, %v", got.GetTotalCount(), got.GetPageCount(), got.GetPage(), tt.wantPage)
+			}
+			if got.GetNextPageToken() != tt.wantNextToken {
+				t.Errorf("NewResponse() next_page_token = %v, want %v", got.GetNextPageToken(), tt.wantNextToken)
+			}
+
+			back, err := paginationpb.ToPagingResponse(got, nil)
+			if err != nil {
+				t.Fatalf("ToPagingResponse() error = %v", err)
+			}
+			if len(back.Pages) != len(res.Pages) {
+				t.Errorf("ToPagingResponse() pages = %v, want %v", back.Pages, res.Pages)
+			}
+			gotActive := []int64{}
+			for _, item := range back.Pages["active"] {
+				gotActive = append(gotActive, item.(*wrapperspb.Int64Value).GetValue())
+			}
+			if !reflect.DeepEqual(gotActive, tt.wantActive) {
+				t.Errorf("ToPagingResponse() active = %v, want %v", gotActive, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestNewResponse_NotProtoMessage(t *testing.T) {
+	res := &pagination.PagingResponse{Pages: pagination.Pages{"active": pagination.PageFetchResult{1}}}
+	if _, err := paginationpb.NewResponse(1, 1, res, &pagination.Setting{}, nil); err == nil {
+		t.Errorf("NewResponse() must fail for non proto.Message items")
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/gemcook/pagination-go/paginationpb
+
+go 1.23
+
+require (
+	github.com/gemcook/pagination-go v0.0.0
+	google.golang.org/protobuf v1.36.12
+)
+
+replace github.com/gemcook/pagination-go => ../
@@ -0,0 +1,451 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: pagination.proto
+
+package paginationpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Direction shows the sort direction.
+type Direction int32
+
+const (
+	Direction_DIRECTION_UNSPECIFIED Direction = 0
+	// sorts by ascending order
+	Direction_DIRECTION_ASC Direction = 1
+	// sorts by descending order
+	Direction_DIRECTION_DESC Direction = 2
+)
+
+// Enum value maps for Direction.
+var (
+	Direction_name = map[int32]string{
+		0: "DIRECTION_UNSPECIFIED",
+		1: "DIRECTION_ASC",
+		2: "DIRECTION_DESC",
+	}
+	Direction_value = map[string]int32{
+		"DIRECTION_UNSPECIFIED": 0,
+		"DIRECTION_ASC":         1,
+		"DIRECTION_DESC":        2,
+	}
+)
+
+func (x Direction) Enum() *Direction {
+	p := new(Direction)
+	*p = x
+	return p
+}
+
+func (x Direction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Direction) Descriptor() protoreflect.EnumDescriptor {
+	return file_pagination_proto_enumTypes[0].Descriptor()
+}
+
+func (Direction) Type() protoreflect.EnumType {
+	return &file_pagination_proto_enumTypes[0]
+}
+
+func (x Direction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Direction.Descriptor instead.
+func (Direction) EnumDescriptor() ([]byte, []int) {
+	return file_pagination_proto_rawDescGZIP(), []int{0}
+}
+
+// Order defines sort order clause.
+type Order struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ColumnName    string                 `protobuf:"bytes,1,opt,name=column_name,json=columnName,proto3" json:"column_name,omitempty"`
+	Direction     Direction              `protobuf:"varint,2,opt,name=direction,proto3,enum=gemcook.pagination.v1.Direction" json:"direction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_pagination_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_pagination_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_pagination_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Order) GetColumnName() string {
+	if x != nil {
+		return x.ColumnName
+	}
+	return ""
+}
+
+func (x *Order) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_DIRECTION_UNSPECIFIED
+}
+
+// PaginationRequest has pagination parameters of a list request.
+type PaginationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// data record count per single page
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// active page number (1~)
+	Page int32    `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Sort []*Order `protobuf:"bytes,3,rep,name=sort,proto3" json:"sort,omitempty"`
+	// opaque cursor of the active page, given by next_page_token of the previous response.
+	// page and offset are ignored if cursor is set.
+	Cursor string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// record offset of the active page (0~). page is ignored if offset is not zero.
+	Offset int32 `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	// AIP-158 style page size. limit is ignored if page_size is set.
+	PageSize int32 `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// AIP-158 style page token. cursor is ignored if page_token is set.
+	PageToken     string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaginationRequest) Reset() {
+	*x = PaginationRequest{}
+	mi := &file_pagination_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaginationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaginationRequest) ProtoMessage() {}
+
+func (x *PaginationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pagination_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaginationRequest.ProtoReflect.Descriptor instead.
+func (*PaginationRequest) Descriptor() ([]byte, []int) {
+	return file_pagination_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PaginationRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *PaginationRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *PaginationRequest) GetSort() []*Order {
+	if x != nil {
+		return x.Sort
+	}
+	return nil
+}
+
+func (x *PaginationRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *PaginationRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *PaginationRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *PaginationRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// Page has records of a single page.
+type Page struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*anypb.Any           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Page) Reset() {
+	*x = Page{}
+	mi := &file_pagination_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Page) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Page) ProtoMessage() {}
+
+func (x *Page) ProtoReflect() protoreflect.Message {
+	mi := &file_pagination_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Page.ProtoReflect.Descriptor instead.
+func (*Page) Descriptor() ([]byte, []int) {
+	return file_pagination_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Page) GetItems() []*anypb.Any {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// PaginationResponse has named pages and totals.
+type PaginationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// pages named "active", "first", "last", "before_distant", "before_near", "after_near" and "after_distant"
+	Pages      map[string]*Page `protobuf:"bytes,1,rep,name=pages,proto3" json:"pages,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	TotalCount int64            `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	PageCount  int32            `protobuf:"varint,3,opt,name=page_count,json=pageCount,proto3" json:"page_count,omitempty"`
+	// active page number (1~)
+	Page int32 `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+	// token of the next page. empty if the active page is the last page.
+	NextPageToken string `protobuf:"bytes,5,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaginationResponse) Reset() {
+	*x = PaginationResponse{}
+	mi := &file_pagination_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaginationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaginationResponse) ProtoMessage() {}
+
+func (x *PaginationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pagination_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaginationResponse.ProtoReflect.Descriptor instead.
+func (*PaginationResponse) Descriptor() ([]byte, []int) {
+	return file_pagination_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PaginationResponse) GetPages() map[string]*Page {
+	if x != nil {
+		return x.Pages
+	}
+	return nil
+}
+
+func (x *PaginationResponse) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *PaginationResponse) GetPageCount() int32 {
+	if x != nil {
+		return x.PageCount
+	}
+	return 0
+}
+
+func (x *PaginationResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *PaginationResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+var File_pagination_proto protoreflect.FileDescriptor
+
+const file_pagination_proto_rawDesc = "" +
+	"\n" +
+	"\x10pagination.proto\x12\x15gemcook.pagination.v1\x1a\x19google/protobuf/any.proto\"h\n" +
+	"\x05Order\x12\x1f\n" +
+	"\vcolumn_name\x18\x01 \x01(\tR\n" +
+	"columnName\x12>\n" +
+	"\tdirection\x18\x02 \x01(\x0e2 .gemcook.pagination.v1.DirectionR\tdirection\"\xdb\x01\n" +
+	"\x11PaginationRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x120\n" +
+	"\x04sort\x18\x03 \x03(\v2\x1c.gemcook.pagination.v1.OrderR\x04sort\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x05R\x06offset\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\"2\n" +
+	"\x04Page\x12*\n" +
+	"\x05items\x18\x01 \x03(\v2\x14.google.protobuf.AnyR\x05items\"\xb3\x02\n" +
+	"\x12PaginationResponse\x12J\n" +
+	"\x05pages\x18\x01 \x03(\v24.gemcook.pagination.v1.PaginationResponse.PagesEntryR\x05pages\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x03R\n" +
+	"totalCount\x12\x1d\n" +
+	"\n" +
+	"page_count\x18\x03 \x01(\x05R\tpageCount\x12\x12\n" +
+	"\x04page\x18\x04 \x01(\x05R\x04page\x12&\n" +
+	"\x0fnext_page_token\x18\x05 \x01(\tR\rnextPageToken\x1aU\n" +
+	"\n" +
+	"PagesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x121\n" +
+	"\x05value\x18\x02 \x01(\v2\x1b.gemcook.pagination.v1.PageR\x05value:\x028\x01*M\n" +
+	"\tDirection\x12\x19\n" +
+	"\x15DIRECTION_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rDIRECTION_ASC\x10\x01\x12\x12\n" +
+	"\x0eDIRECTION_DESC\x10\x02B/Z-github.com/gemcook/pagination-go/paginationpbb\x06proto3"
+
+var (
+	file_pagination_proto_rawDescOnce sync.Once
+	file_pagination_proto_rawDescData []byte
+)
+
+func file_pagination_proto_rawDescGZIP() []byte {
+	file_pagination_proto_rawDescOnce.Do(func() {
+		file_pagination_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pagination_proto_rawDesc), len(file_pagination_proto_rawDesc)))
+	})
+	return file_pagination_proto_rawDescData
+}
+
+var file_pagination_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pagination_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_pagination_proto_goTypes = []any{
+	(Direction)(0),             // 0: gemcook.pagination.v1.Direction
+	(*Order)(nil),              // 1: gemcook.pagination.v1.Order
+	(*PaginationRequest)(nil),  // 2: gemcook.pagination.v1.PaginationRequest
+	(*Page)(nil),               // 3: gemcook.pagination.v1.Page
+	(*PaginationResponse)(nil), // 4: gemcook.pagination.v1.PaginationResponse
+	nil,                        // 5: gemcook.pagination.v1.PaginationResponse.PagesEntry
+	(*anypb.Any)(nil),          // 6: google.protobuf.Any
+}
+var file_pagination_proto_depIdxs = []int32{
+	0, // 0: gemcook.pagination.v1.Order.direction:type_name -> gemcook.pagination.v1.Direction
+	1, // 1: gemcook.pagination.v1.PaginationRequest.sort:type_name -> gemcook.pagination.v1.Order
+	6, // 2: gemcook.pagination.v1.Page.items:type_name -> google.protobuf.Any
+	5, // 3: gemcook.pagination.v1.PaginationResponse.pages:type_name -> gemcook.pagination.v1.PaginationResponse.PagesEntry
+	3, // 4: gemcook.pagination.v1.PaginationResponse.PagesEntry.value:type_name -> gemcook.pagination.v1.Page
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_pagination_proto_init() }
+func file_pagination_proto_init() {
+	if File_pagination_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pagination_proto_rawDesc), len(file_pagination_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pagination_proto_goTypes,
+		DependencyIndexes: file_pagination_proto_depIdxs,
+		EnumInfos:         file_pagination_proto_enumTypes,
+		MessageInfos:      file_pagination_proto_msgTypes,
+	}.Build()
+	File_pagination_proto = out.File
+	file_pagination_proto_goTypes = nil
+	file_pagination_proto_depIdxs = nil
+}
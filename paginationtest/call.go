@@ -0,0 +1,49 @@
+package paginationtest
+
+import (
+	"fmt"
+	"strings"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// CountCall returns the call of Count with the condition.
+func CountCall(cond interface{}) Call {
+	return Call{Method: MethodCount, Cond: cond}
+}
+
+// FetchPageCall returns the call of FetchPage with the condition and the range.
+func FetchPageCall(cond interface{}, limit, offset int, orders ...*pagination.Order) Call {
+	return Call{Method: MethodFetchPage, Cond: cond, Input: &pagination.PageFetchInput{Limit: limit, Offset: offset, Orders: orders}}
+}
+
+// String formats the call like "FetchPage(limit 10, offset 20)".
+func (c Call) String() string {
+	if c.Input == nil {
+		return c.Method + "()"
+	}
+	return fmt.Sprintf("%s(limit %d, offset %d%s)", c.Method, c.Input.Limit, c.Input.Offset, formatOrders(c.Input.Orders))
+}
+
+func formatOrders(orders []*pagination.Order) string {
+	s := ""
+	for _, o := range orders {
+		d := "+"
+		if o.Direction == pagination.DirectionDesc {
+			d = "-"
+		}
+		s += d + o.ColumnName
+	}
+	if s == "" {
+		return ""
+	}
+	return ", sort " + s
+}
+
+func formatCalls(calls []Call) string {
+	s := make([]string, len(calls))
+	for i, c := range calls {
+		s[i] = c.String()
+	}
+	return "[" + strings.Join(s, " ") + "]"
+}
@@ -0,0 +1,156 @@
+package paginationtest
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// Methods of PageFetcher recorded in Call.
+const (
+	MethodCount     = "Count"
+	MethodFetchPage = "FetchPage"
+)
+
+// Call is a call of Fetcher.
+type Call struct {
+	Method string
+	Cond   interface{}
+	// Input is nil for Count.
+	Input *pagination.PageFetchInput
+}
+
+// Fetcher is a fake pagination.PageFetcher of in-memory records, which records its calls.
+type Fetcher struct {
+	Records []interface{}
+	// Match reports whether the record matches the condition. All records match if nil.
+	Match func(cond interface{}, record interface{}) bool
+	// Compare compares two records by the column, and returns a negative number, 0 or a positive number.
+	// Orders are ignored if nil, and the records are served as they are.
+	Compare func(a, b interface{}, column string) int
+	// CountErr and FetchPageErr are returned by Count and FetchPage if not nil.
+	CountErr     error
+	FetchPageErr error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewFetcher returns a fake fetcher of the records.
+func NewFetcher(records ...interface{}) *Fetcher {
+	return &Fetcher{Records: records}
+}
+
+// Count counts records matching the condition.
+func (f *Fetcher) Count(cond interface{}) (int, error) {
+	f.record(Call{Method: MethodCount, Cond: cond})
+	if f.CountErr != nil {
+		return 0, f.CountErr
+	}
+	return len(f.match(cond)), nil
+}
+
+// FetchPage appends records matching the condition in the range of input.
+func (f *Fetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	in := *input
+	f.record(Call{Method: MethodFetchPage, Cond: cond, Input: &in})
+	if f.FetchPageErr != nil {
+		return f.FetchPageErr
+	}
+
+	records := f.match(cond)
+	f.sort(records, input.Orders)
+	if input.Offset >= len(records) {
+		return nil
+	}
+	end := input.Offset + input.Limit
+	if end > len(records) {
+		end = len(records)
+	}
+	*result = append(*result, records[input.Offset:end]...)
+	return nil
+}
+
+// Calls returns the recorded calls.
+func (f *Fetcher) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// Reset clears the recorded calls.
+func (f *Fetcher) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+// AssertCalls fails the test if the recorded calls differ from want.
+func (f *Fetcher) AssertCalls(t testing.TB, want ...Call) {
+	t.Helper()
+	got := f.Calls()
+	if len(got) != len(want) {
+		t.Fatalf("got %d calls %s, want %d calls %s", len(got), formatCalls(got), len(want), formatCalls(want))
+	}
+	for i := range got {
+		if !callEqual(got[i], want[i]) {
+			t.Errorf("call %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+// callEqual compares calls, regarding nil and empty orders as equal.
+func callEqual(a, b Call) bool {
+	if a.Method != b.Method || !reflect.DeepEqual(a.Cond, b.Cond) {
+		return false
+	}
+	if a.Input == nil || b.Input == nil {
+		return a.Input == b.Input
+	}
+	if a.Input.Limit != b.Input.Limit || a.Input.Offset != b.Input.Offset || len(a.Input.Orders) != len(b.Input.Orders) {
+		return false
+	}
+	for i := range a.Input.Orders {
+		if *a.Input.Orders[i] != *b.Input.Orders[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *Fetcher) record(call Call) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+}
+
+func (f *Fetcher) match(cond interface{}) []interface{} {
+	records := make([]interface{}, 0, len(f.Records))
+	for _, record := range f.Records {
+		if f.Match == nil || f.Match(cond, record) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func (f *Fetcher) sort(records []interface{}, orders []*pagination.Order) {
+	if f.Compare == nil || len(orders) == 0 {
+		return
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, o := range orders {
+			c := f.Compare(records[i], records[j], o.ColumnName)
+			if o.Direction == pagination.DirectionDesc {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,142 @@
+package paginationtest_test
+
+import (
+	"errors"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/paginationtest"
+)
+
+type fruit struct {
+	ID    int
+	Name  string
+	Price int
+}
+
+var fruits = []interface{}{
+	fruit{1, "Apple", 100},
+	fruit{2, "Banana", 50},
+	fruit{3, "Cherry", 400},
+	fruit{4, "Durian", 1000},
+	fruit{5, "Elderberry", 300},
+	fruit{6, "Fig", 200},
+}
+
+func newFruitFetcher() *paginationtest.Fetcher {
+	f := paginationtest.NewFetcher(fruits...)
+	f.Match = func(cond interface{}, record interface{}) bool {
+		return record.(fruit).Price <= cond.(int)
+	}
+	f.Compare = func(a, b interface{}, column string) int {
+		switch column {
+		case "price":
+			return a.(fruit).Price - b.(fruit).Price
+		default:
+			return a.(fruit).ID - b.(fruit).ID
+		}
+	}
+	return f
+}
+
+func TestRunFetcherSuite(t *testing.T) {
+	paginationtest.RunFetcherSuite(t, func(t *testing.T) *paginationtest.Fixture {
+		return &paginationtest.Fixture{
+			Fetcher:   newFruitFetcher(),
+			Cond:      500,
+			EmptyCond: 10,
+			Orders:    []*pagination.Order{{ColumnName: "price", Direction: pagination.DirectionDesc}},
+		}
+	})
+}
+
+func TestFetcher_AssertCalls(t *testing.T) {
+	records := make([]interface{}, 95)
+	for i := range records {
+		records[i] = i + 1
+	}
+	orders := []*pagination.Order{{ColumnName: "id", Direction: pagination.DirectionAsc}}
+
+	tests := []struct {
+		name string
+		page int
+		want []paginationtest.Call
+	}{
+		{
+			name: "first page",
+			page: 1,
+			want: []paginationtest.Call{
+				paginationtest.CountCall("cond"),
+				paginationtest.FetchPageCall("cond", 50, 0, orders...),
+				paginationtest.FetchPageCall("cond", 10, 90, orders...),
+			},
+		},
+		{
+			name: "middle page",
+			page: 5,
+			want: []paginationtest.Call{
+				paginationtest.CountCall("cond"),
+				paginationtest.FetchPageCall("cond", 50, 20, orders...),
+				paginationtest.FetchPageCall("cond", 10, 0, orders...),
+				paginationtest.FetchPageCall("cond", 10, 90, orders...),
+			},
+		},
+		{
+			name: "last page",
+			page: 10,
+			want: []paginationtest.Call{
+				paginationtest.CountCall("cond"),
+				paginationtest.FetchPageCall("cond", 50, 50, orders...),
+				paginationtest.FetchPageCall("cond", 10, 0, orders...),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := paginationtest.NewFetcher(records...)
+			_, _, _, err := pagination.Fetch(f, &pagination.Setting{Limit: 10, Page: tt.page, Cond: "cond", Orders: orders})
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.AssertCalls(t, tt.want...)
+		})
+	}
+}
+
+func TestFetcher_FetchPage(t *testing.T) {
+	f := newFruitFetcher()
+	var result pagination.PageFetchResult
+	input := &pagination.PageFetchInput{Limit: 2, Offset: 1, Orders: []*pagination.Order{{ColumnName: "price", Direction: pagination.DirectionAsc}}}
+	if err := f.FetchPage(500, input, &result); err != nil {
+		t.Fatal(err)
+	}
+	want := pagination.PageFetchResult{fruits[0], fruits[5]}
+	if len(result) != len(want) || result[0] != want[0] || result[1] != want[1] {
+		t.Errorf("result = %v, want %v", result, want)
+	}
+
+	f.Reset()
+	if calls := f.Calls(); len(calls) != 0 {
+		t.Errorf("calls after Reset = %v", calls)
+	}
+}
+
+func TestFetcher_Errors(t *testing.T) {
+	errCount := errors.New("count failed")
+	f := paginationtest.NewFetcher(fruits...)
+	f.CountErr = errCount
+
+	_, _, _, err := pagination.Fetch(f, &pagination.Setting{Limit: 2, Page: 1})
+	var fetcherErr *pagination.FetcherError
+	if !errors.As(err, &fetcherErr) || fetcherErr.Stage != pagination.StageCount || !errors.Is(err, errCount) {
+		t.Errorf("err = %v, want count FetcherError", err)
+	}
+	f.AssertCalls(t, paginationtest.CountCall(nil))
+
+	errFetch := errors.New("fetch failed")
+	f = paginationtest.NewFetcher(fruits...)
+	f.FetchPageErr = errFetch
+	if _, _, _, err := pagination.Fetch(f, &pagination.Setting{Limit: 2, Page: 1}); !errors.Is(err, errFetch) {
+		t.Errorf("err = %v, want %v", err, errFetch)
+	}
+}
@@ -0,0 +1,176 @@
+// Package paginationtest provides a conformance test suite of pagination.PageFetcher,
+// and a fake fetcher recording its calls.
+package paginationtest
+
+import (
+	"reflect"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// Fixture is a fetcher under test and conditions of its records.
+type Fixture struct {
+	Fetcher pagination.PageFetcher
+	// Cond matches at least 3 records.
+	Cond interface{}
+	// EmptyCond matches no records.
+	EmptyCond interface{}
+	// Orders is a total order of the records, such as by a unique id.
+	Orders []*pagination.Order
+	// Equal compares two records. reflect.DeepEqual is used if nil.
+	Equal func(a, b interface{}) bool
+}
+
+// Factory returns a new fixture for each test of the suite.
+type Factory func(t *testing.T) *Fixture
+
+// RunFetcherSuite runs conformance tests of the fetcher given by factory.
+// It checks that Count agrees with FetchPage, that pages are stable and honour Limit, Offset and Orders,
+// and that the ranges beyond the records and empty conditions give no records.
+func RunFetcherSuite(t *testing.T, factory Factory) {
+	t.Run("CountAgreesWithFetch", func(t *testing.T) {
+		f := factory(t)
+		all := fetchAll(t, f, f.Orders)
+		if len(all) < 3 {
+			t.Fatalf("Cond matches %d records, want at least 3", len(all))
+		}
+	})
+
+	t.Run("StableOrder", func(t *testing.T) {
+		f := factory(t)
+		all := fetchAll(t, f, f.Orders)
+		again := fetchAll(t, f, f.Orders)
+		assertRecords(t, f, "second fetch", again, all)
+	})
+
+	t.Run("PagesConcatenate", func(t *testing.T) {
+		f := factory(t)
+		all := fetchAll(t, f, f.Orders)
+		for _, limit := range []int{1, 2, 3} {
+			var paged pagination.PageFetchResult
+			for offset := 0; offset < len(all); offset += limit {
+				page := fetchPage(t, f, f.Cond, limit, offset, f.Orders)
+				want := limit
+				if rest := len(all) - offset; rest < want {
+					want = rest
+				}
+				if len(page) != want {
+					t.Fatalf("FetchPage(limit %d, offset %d) returned %d records, want %d", limit, offset, len(page), want)
+				}
+				paged = append(paged, page...)
+			}
+			assertRecords(t, f, "pages", paged, all)
+		}
+	})
+
+	t.Run("Orders", func(t *testing.T) {
+		f := factory(t)
+		if len(f.Orders) == 0 {
+			t.Skip("Orders is empty")
+		}
+		all := fetchAll(t, f, f.Orders)
+		reversed := fetchAll(t, f, reverseOrders(f.Orders))
+		want := make(pagination.PageFetchResult, len(all))
+		for i, record := range all {
+			want[len(all)-1-i] = record
+		}
+		assertRecords(t, f, "reversed orders", reversed, want)
+	})
+
+	t.Run("Bounds", func(t *testing.T) {
+		f := factory(t)
+		all := fetchAll(t, f, f.Orders)
+		n := len(all)
+
+		last := fetchPage(t, f, f.Cond, 10, n-1, f.Orders)
+		assertRecords(t, f, "offset at the last record", last, all[n-1:])
+
+		for _, offset := range []int{n, n + 10} {
+			page := fetchPage(t, f, f.Cond, 10, offset, f.Orders)
+			if len(page) != 0 {
+				t.Errorf("FetchPage(offset %d) beyond %d records returned %d records", offset, n, len(page))
+			}
+		}
+	})
+
+	t.Run("AppendsToResult", func(t *testing.T) {
+		f := factory(t)
+		sentinel := struct{}{}
+		result := pagination.PageFetchResult{sentinel}
+		input := &pagination.PageFetchInput{Limit: 2, Offset: 0, Orders: f.Orders}
+		if err := f.Fetcher.FetchPage(f.Cond, input, &result); err != nil {
+			t.Fatalf("FetchPage: %v", err)
+		}
+		if len(result) != 3 || result[0] != sentinel {
+			t.Errorf("FetchPage replaced the result, want 2 records appended to 1")
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		f := factory(t)
+		count, err := f.Fetcher.Count(f.EmptyCond)
+		if err != nil {
+			t.Fatalf("Count: %v", err)
+		}
+		if count != 0 {
+			t.Errorf("Count(EmptyCond) = %d, want 0", count)
+		}
+		page := fetchPage(t, f, f.EmptyCond, 10, 0, f.Orders)
+		if len(page) != 0 {
+			t.Errorf("FetchPage(EmptyCond) returned %d records, want 0", len(page))
+		}
+	})
+}
+
+// fetchAll fetches all records of Cond, and checks that Count agrees with them.
+func fetchAll(t *testing.T, f *Fixture, orders []*pagination.Order) pagination.PageFetchResult {
+	t.Helper()
+	count, err := f.Fetcher.Count(f.Cond)
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	all := fetchPage(t, f, f.Cond, count+10, 0, orders)
+	if len(all) != count {
+		t.Fatalf("Count = %d, but FetchPage returned %d records", count, len(all))
+	}
+	return all
+}
+
+func fetchPage(t *testing.T, f *Fixture, cond interface{}, limit, offset int, orders []*pagination.Order) pagination.PageFetchResult {
+	t.Helper()
+	var result pagination.PageFetchResult
+	input := &pagination.PageFetchInput{Limit: limit, Offset: offset, Orders: orders}
+	if err := f.Fetcher.FetchPage(cond, input, &result); err != nil {
+		t.Fatalf("FetchPage(limit %d, offset %d): %v", limit, offset, err)
+	}
+	return result
+}
+
+func assertRecords(t *testing.T, f *Fixture, name string, got, want pagination.PageFetchResult) {
+	t.Helper()
+	equal := f.Equal
+	if equal == nil {
+		equal = reflect.DeepEqual
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d records, want %d", name, len(got), len(want))
+	}
+	for i := range got {
+		if !equal(got[i], want[i]) {
+			t.Fatalf("%s: record %d = %v, want %v", name, i, got[i], want[i])
+		}
+	}
+}
+
+func reverseOrders(orders []*pagination.Order) []*pagination.Order {
+	reversed := make([]*pagination.Order, len(orders))
+	for i, o := range orders {
+		d := pagination.DirectionDesc
+		if o.Direction == pagination.DirectionDesc {
+			d = pagination.DirectionAsc
+		}
+		reversed[i] = &pagination.Order{ColumnName: o.ColumnName, Direction: d}
+	}
+	return reversed
+}
@@ -1,8 +1,16 @@
 package pagination
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 // Query has pagination query parameters.
@@ -11,6 +19,8 @@ type Query struct {
 	Page    int
 	Sort    []*Order
 	Enabled bool
+	// Offset is set when the record offset is given instead of page.
+	Offset int
 }
 
 // Init initialize pagination query parameters.
@@ -20,71 +30,257 @@ func (q *Query) Init() {
 	q.Enabled = true
 }
 
+// Param is a query parameter name and its aliases.
+// When several of them are given, Name wins over Aliases, and earlier aliases win over later ones.
+type Param struct {
+	Name    string
+	Aliases []string
+}
+
+func (p Param) lookup(get func(key string) []string) []string {
+	if p.Name != "" {
+		if vs := get(p.Name); len(vs) > 0 {
+			return vs
+		}
+	}
+	for _, alias := range p.Aliases {
+		if vs := get(alias); len(vs) > 0 {
+			return vs
+		}
+	}
+	return nil
+}
+
+// Parser parses query parameters with configurable parameter names and default values.
+type Parser struct {
+	Limit      Param
+	Page       Param
+	Pagination Param
+	Sort       Param
+	Offset     Param
+
+	// DefaultLimit is used when limit is not given. 10 is used if zero.
+	DefaultLimit int
+	// DefaultPage is used when page is not given. 1 is used if zero.
+	DefaultPage int
+	// MaxBodyBytes is the limit of the JSON body read by ParseRequest. 1 MiB is used if zero.
+	MaxBodyBytes int64
+}
+
+// NewParser returns a parser which reads limit, page, pagination, sort and offset.
+func NewParser() *Parser {
+	return &Parser{
+		Limit:      Param{Name: "limit"},
+		Page:       Param{Name: "page"},
+		Pagination: Param{Name: "pagination"},
+		Sort:       Param{Name: "sort"},
+		Offset:     Param{Name: "offset"},
+	}
+}
+
+// DefaultParser is the parser used by ParseQuery and ParseMap.
+var DefaultParser = NewParser()
+
 // ParseQuery parses URL query string to get limit, page and sort
 func ParseQuery(queryStr string) *Query {
+	return DefaultParser.ParseQuery(queryStr)
+}
 
-	// Set default values.
-	p := &Query{}
-	p.Init()
+// ParseMap parses URL parameters map to get limit, page and sort
+func ParseMap(qs map[string]string) *Query {
+	return DefaultParser.ParseMap(qs)
+}
+
+// ParseValues parses URL query values to get limit, page and sort
+func ParseValues(values url.Values) *Query {
+	return DefaultParser.ParseValues(values)
+}
 
+// ParseRequest parses HTTP request to get limit, page and sort
+func ParseRequest(r *http.Request) (*Query, error) {
+	return DefaultParser.ParseRequest(r)
+}
+
+// ParseQuery parses URL query string to get limit, page and sort
+func (ps *Parser) ParseQuery(queryStr string) *Query {
 	u, err := url.Parse(queryStr)
 	if err != nil {
-		return p
+		return ps.defaultQuery()
 	}
-	query := u.Query()
+	return ps.ParseValues(u.Query())
+}
 
-	if limitStr := query.Get("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			p.Limit = limit
+// ParseMap parses URL parameters map to get limit, page and sort
+func (ps *Parser) ParseMap(qs map[string]string) *Query {
+	return ps.parse(func(key string) []string {
+		if v, ok := qs[key]; ok {
+			return []string{v}
 		}
+		return nil
+	})
+}
+
+// ParseValues parses URL query values to get limit, page and sort.
+// Repeated sort parameters like 'sort=-price&sort=+name' are joined in order.
+func (ps *Parser) ParseValues(values url.Values) *Query {
+	return ps.parse(valuesGetter(values))
+}
+
+// ParseRequest parses URL query of the request to get limit, page and sort.
+// If the POST request has a JSON object body like '{"limit": 20, "page": 3, "sort": ["-price"]}',
+// its parameters take precedence over the URL query.
+// Bodies of other methods, bodies which are not JSON objects and bodies larger than MaxBodyBytes are ignored.
+// The request body is restored, so it can be read again by the handler.
+func (ps *Parser) ParseRequest(r *http.Request) (*Query, error) {
+	get := valuesGetter(r.URL.Query())
+
+	if r.Method != http.MethodPost || r.Body == nil || r.Body == http.NoBody || !isJSONContent(r.Header.Get("Content-Type")) {
+		return ps.parse(get), nil
 	}
 
-	if pageStr := query.Get("page"); pageStr != "" {
-		if page, err := strconv.Atoi(pageStr); err == nil {
-			p.Page = page
+	maxBytes := ps.MaxBodyBytes
+	if maxBytes == 0 {
+		maxBytes = defaultMaxBodyBytes
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+	if err != nil {
+		r.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > maxBytes {
+		// too large for a search body, so leave the rest unread for the handler
+		r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		return ps.parse(get), nil
+	}
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return ps.parse(get), nil
+	}
+	getBody, err := jsonGetter(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBody, err)
+	}
+	getQuery := get
+	get = func(key string) []string {
+		if vs := getBody(key); len(vs) > 0 {
+			return vs
 		}
+		return getQuery(key)
 	}
+	return ps.parse(get), nil
+}
 
-	if pageStr := query.Get("pagination"); pageStr != "" {
-		if pageStr == "false" {
-			p.Enabled = false
+// defaultMaxBodyBytes is the limit of the JSON body read by ParseRequest when MaxBodyBytes is zero.
+const defaultMaxBodyBytes = 1 << 20
+
+// readCloser reads the restored body, and closes the original one.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func valuesGetter(values url.Values) func(key string) []string {
+	return func(key string) []string {
+		vs := make([]string, 0, len(values[key]))
+		for _, v := range values[key] {
+			if v != "" {
+				vs = append(vs, v)
+			}
 		}
+		return vs
 	}
+}
 
-	p.Sort = ParseSort(queryStr)
-	return p
+func isJSONContent(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
 }
 
-// ParseMap parses URL parameters map to get limit, page and sort
-func ParseMap(qs map[string]string) *Query {
+// jsonGetter reads top level parameters of JSON object.
+// Numbers and booleans are read as strings, and arrays are read as repeated parameters.
+func jsonGetter(body []byte) (func(key string) []string, error) {
+	params := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&params); err != nil {
+		return nil, err
+	}
 
-	// Set default values.
+	var toStrings func(v interface{}) []string
+	toStrings = func(v interface{}) []string {
+		switch v := v.(type) {
+		case string:
+			return []string{v}
+		case json.Number:
+			return []string{v.String()}
+		case bool:
+			return []string{strconv.FormatBool(v)}
+		case []interface{}:
+			vs := []string{}
+			for _, e := range v {
+				vs = append(vs, toStrings(e)...)
+			}
+			return vs
+		default:
+			return nil
+		}
+	}
+
+	return func(key string) []string {
+		return toStrings(params[key])
+	}, nil
+}
+
+func (ps *Parser) defaultQuery() *Query {
 	p := &Query{}
 	p.Init()
+	if ps.DefaultLimit != 0 {
+		p.Limit = ps.DefaultLimit
+	}
+	if ps.DefaultPage != 0 {
+		p.Page = ps.DefaultPage
+	}
+	p.Sort = []*Order{}
+	return p
+}
+
+func (ps *Parser) parse(get func(key string) []string) *Query {
+
+	// Set default values.
+	p := ps.defaultQuery()
 
-	if limitStr, ok := qs["limit"]; ok {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
+	if limitStr := ps.Limit.lookup(get); limitStr != nil {
+		if limit, err := strconv.Atoi(limitStr[0]); err == nil {
 			p.Limit = limit
 		}
 	}
 
-	if pageStr, ok := qs["page"]; ok {
-		if page, err := strconv.Atoi(pageStr); err == nil {
+	if pageStr := ps.Page.lookup(get); pageStr != nil {
+		if page, err := strconv.Atoi(pageStr[0]); err == nil {
 			p.Page = page
 		}
 	}
 
-	if pageStr, ok := qs["pagination"]; ok {
-		if pageStr == "false" {
-			p.Enabled = false
+	if offsetStr := ps.Offset.lookup(get); offsetStr != nil {
+		if offset, err := strconv.Atoi(offsetStr[0]); err == nil {
+			p.Offset = offset
 		}
 	}
 
-	orders := []*Order{}
+	if pageStr := ps.Pagination.lookup(get); pageStr != nil {
+		if pageStr[0] == "false" {
+			p.Enabled = false
+		}
+	}
 
-	if sort, ok := qs["sort"]; ok {
-		orders = ParseOrders(sort)
+	for _, sort := range ps.Sort.lookup(get) {
+		p.Sort = append(p.Sort, ParseOrders(sort)...)
 	}
-	p.Sort = orders
 	return p
 }
@@ -1,7 +1,12 @@
 package pagination_test
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 
 	pagination "github.com/gemcook/pagination-go"
@@ -19,6 +24,7 @@ func TestParseQuery(t *testing.T) {
 		{"default", args{"https://example.com/fruits?price_range=0,100"}, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: true}},
 		{"limit=10, page=5", args{"https://example.com/fruits?price_range=0,100&page=5&limit=10"}, &pagination.Query{Limit: 10, Page: 5, Sort: []*pagination.Order{}, Enabled: true}},
 		{"pagination disabled", args{"https://example.com/fruits?price_range=0,100&pagination=false"}, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: false}},
+		{"offset=40, limit=20", args{"https://example.com/fruits?offset=40&limit=20"}, &pagination.Query{Limit: 20, Page: 1, Sort: []*pagination.Order{}, Enabled: true, Offset: 40}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -38,6 +44,7 @@ func TestParseMap(t *testing.T) {
 		{"default", map[string]string{}, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: true}},
 		{"limit=10, page=2", map[string]string{"limit": "10", "page": "2"}, &pagination.Query{Limit: 10, Page: 2, Sort: []*pagination.Order{}, Enabled: true}},
 		{"pagination=false", map[string]string{"pagination": "false"}, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: false}},
+		{"offset=15", map[string]string{"offset": "15"}, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: true, Offset: 15}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -47,3 +54,156 @@ func TestParseMap(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_ParseQuery(t *testing.T) {
+	jsonAPI := &pagination.Parser{
+		Limit: pagination.Param{Name: "page[size]"},
+		Page:  pagination.Param{Name: "page[number]"},
+		Sort:  pagination.Param{Name: "sort"},
+	}
+	aliased := pagination.NewParser()
+	aliased.Limit.Aliases = []string{"per_page", "size"}
+	aliased.DefaultLimit = 20
+	aliased.DefaultPage = 2
+
+	tests := []struct {
+		name     string
+		parser   *pagination.Parser
+		queryStr string
+		want     *pagination.Query
+	}{
+		{"json:api", jsonAPI, "https://example.com/fruits?page%5Bsize%5D=5&page%5Bnumber%5D=3&sort=-price", &pagination.Query{Limit: 5, Page: 3, Sort: []*pagination.Order{&pagination.Order{Direction: pagination.DirectionDesc, ColumnName: "price"}}, Enabled: true}},
+		{"json:api ignores limit", jsonAPI, "https://example.com/fruits?limit=5&page=3", &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: true}},
+		{"defaults", aliased, "https://example.com/fruits", &pagination.Query{Limit: 20, Page: 2, Sort: []*pagination.Order{}, Enabled: true}},
+		{"alias", aliased, "https://example.com/fruits?per_page=30", &pagination.Query{Limit: 30, Page: 2, Sort: []*pagination.Order{}, Enabled: true}},
+		{"name wins over alias", aliased, "https://example.com/fruits?size=40&limit=50", &pagination.Query{Limit: 50, Page: 2, Sort: []*pagination.Order{}, Enabled: true}},
+		{"earlier alias wins", aliased, "https://example.com/fruits?size=40&per_page=30", &pagination.Query{Limit: 30, Page: 2, Sort: []*pagination.Order{}, Enabled: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.parser.ParseQuery(tt.queryStr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parser.ParseQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_ParseMap(t *testing.T) {
+	parser := pagination.NewParser()
+	parser.Limit.Aliases = []string{"per_page"}
+	parser.Pagination.Name = "paging"
+
+	tests := []struct {
+		name string
+		qs   map[string]string
+		want *pagination.Query
+	}{
+		{"alias", map[string]string{"per_page": "25"}, &pagination.Query{Limit: 25, Page: 1, Sort: []*pagination.Order{}, Enabled: true}},
+		{"renamed pagination", map[string]string{"paging": "false"}, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: false}},
+		{"old pagination name is ignored", map[string]string{"pagination": "false"}, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parser.ParseMap(tt.qs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parser.ParseMap() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		values url.Values
+		want   *pagination.Query
+	}{
+		{"default", url.Values{}, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: true}},
+		{"limit=5, page=2", url.Values{"limit": {"5"}, "page": {"2"}}, &pagination.Query{Limit: 5, Page: 2, Sort: []*pagination.Order{}, Enabled: true}},
+		{"first value wins", url.Values{"limit": {"", "5", "6"}}, &pagination.Query{Limit: 5, Page: 1, Sort: []*pagination.Order{}, Enabled: true}},
+		{"repeated sort", url.Values{"sort": {"-price", "+name-id"}}, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{
+			&pagination.Order{Direction: pagination.DirectionDesc, ColumnName: "price"},
+			&pagination.Order{Direction: pagination.DirectionAsc, ColumnName: "name"},
+			&pagination.Order{Direction: pagination.DirectionDesc, ColumnName: "id"},
+		}, Enabled: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pagination.ParseValues(tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseValues() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		want        *pagination.Query
+		wantErr     bool
+	}{
+		{"GET", http.MethodGet, "/fruits?limit=5&page=2&sort=-price&sort=%2Bname", "", "", &pagination.Query{Limit: 5, Page: 2, Sort: []*pagination.Order{
+			&pagination.Order{Direction: pagination.DirectionDesc, ColumnName: "price"},
+			&pagination.Order{Direction: pagination.DirectionAsc, ColumnName: "name"},
+		}, Enabled: true}, false},
+		{"POST JSON", http.MethodPost, "/fruits/search", "application/json; charset=utf-8", `{"limit": 20, "page": "3", "pagination": true, "sort": ["-price", "+name"], "price_range": [100, 300]}`, &pagination.Query{Limit: 20, Page: 3, Sort: []*pagination.Order{
+			&pagination.Order{Direction: pagination.DirectionDesc, ColumnName: "price"},
+			&pagination.Order{Direction: pagination.DirectionAsc, ColumnName: "name"},
+		}, Enabled: true}, false},
+		{"JSON body wins over URL query", http.MethodPost, "/fruits/search?limit=5&page=2", "application/json", `{"limit": 20, "offset": 40}`, &pagination.Query{Limit: 20, Page: 2, Sort: []*pagination.Order{}, Enabled: true, Offset: 40}, false},
+		{"pagination disabled in JSON", http.MethodPost, "/fruits/search", "application/vnd.api+json", `{"pagination": false}`, &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: false}, false},
+		{"empty JSON body", http.MethodPost, "/fruits/search?page=2", "application/json", "", &pagination.Query{Limit: 10, Page: 2, Sort: []*pagination.Order{}, Enabled: true}, false},
+		{"form body is ignored", http.MethodPost, "/fruits/search", "application/x-www-form-urlencoded", "limit=20", &pagination.Query{Limit: 10, Page: 1, Sort: []*pagination.Order{}, Enabled: true}, false},
+		{"invalid JSON", http.MethodPost, "/fruits/search", "application/json", `{"limit": `, nil, true},
+		{"JSON array is ignored", http.MethodPost, "/fruits/batch?page=2", "application/json", `[{"limit": 20}]`, &pagination.Query{Limit: 10, Page: 2, Sort: []*pagination.Order{}, Enabled: true}, false},
+		{"PUT body is ignored", http.MethodPut, "/fruits/1?page=2", "application/json", `{"page": 5}`, &pagination.Query{Limit: 10, Page: 2, Sort: []*pagination.Order{}, Enabled: true}, false},
+		{"GET body is ignored", http.MethodGet, "/fruits?page=2", "application/json", `{"page": 5}`, &pagination.Query{Limit: 10, Page: 2, Sort: []*pagination.Order{}, Enabled: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			got, err := pagination.ParseRequest(r)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRequest() = %+v, want %+v", got, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != tt.body {
+				t.Errorf("ParseRequest() must restore body = %q, got %q", tt.body, body)
+			}
+		})
+	}
+}
+
+func TestParser_ParseRequest_MaxBodyBytes(t *testing.T) {
+	parser := pagination.NewParser()
+	parser.MaxBodyBytes = 16
+	body := `{"limit": 20, "page": 3, "sort": ["-price"]}`
+	r := httptest.NewRequest(http.MethodPost, "/fruits/search?page=2", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+
+	got, err := parser.ParseRequest(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pagination.Query{Limit: 10, Page: 2, Sort: []*pagination.Order{}, Enabled: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRequest() = %+v, want %+v", got, want)
+	}
+	restored, _ := ioutil.ReadAll(r.Body)
+	if string(restored) != body {
+		t.Errorf("ParseRequest() must restore body = %q, got %q", body, restored)
+	}
+}
@@ -0,0 +1,224 @@
+// Package relay provides GraphQL Relay style connections on top of pagination.PageFetcher.
+//
+// Fetchers are paginated with offset based cursors compatible with graphql-relay-js.
+// Fetchers which also implement pagination.KeysetFetcher are paginated with their own keyset cursors.
+package relay
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// ErrInvalidCursor is returned when the cursor can not be decoded.
+// It is pagination.ErrInvalidCursor, so pagination.HTTPStatus maps it to 400.
+var ErrInvalidCursor = pagination.ErrInvalidCursor
+
+// Setting is Relay connection arguments and fetching condition.
+type Setting struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+	Cond   interface{}
+	Orders []*pagination.Order
+	// DefaultFirst is used when neither First nor Last is given. 10 is used if zero.
+	DefaultFirst int
+}
+
+// Connection is a Relay connection.
+type Connection struct {
+	Edges      []*Edge  `json:"edges"`
+	PageInfo   PageInfo `json:"pageInfo"`
+	TotalCount int      `json:"totalCount"`
+}
+
+// Edge is a node with its cursor.
+type Edge struct {
+	Cursor string      `json:"cursor"`
+	Node   interface{} `json:"node"`
+}
+
+// PageInfo has Relay page info.
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+const offsetCursorPrefix = "arrayconnection:"
+
+// OffsetCursor returns the offset based cursor, which is compatible with graphql-relay-js.
+func OffsetCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(offsetCursorPrefix + strconv.Itoa(offset)))
+}
+
+// ParseOffsetCursor returns the offset of the offset based cursor.
+func ParseOffsetCursor(cursor string) (int, error) {
+	b, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(b), offsetCursorPrefix) {
+		return 0, ErrInvalidCursor
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(b), offsetCursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return offset, nil
+}
+
+// Fetch returns Relay connection using arbitrary record fetcher.
+func Fetch(fetcher pagination.PageFetcher, setting *Setting) (*Connection, error) {
+	if setting.First != nil && *setting.First < 0 {
+		return nil, fmt.Errorf("%w: first must be >= 0", pagination.ErrInvalidLimit)
+	}
+	if setting.Last != nil && *setting.Last < 0 {
+		return nil, fmt.Errorf("%w: last must be >= 0", pagination.ErrInvalidLimit)
+	}
+
+	totalCount, err := fetcher.Count(setting.Cond)
+	if err != nil {
+		return nil, &pagination.FetcherError{Stage: pagination.StageCount, Err: err}
+	}
+
+	if kf, ok := pagination.AsKeysetFetcher(fetcher); ok {
+		return fetchKeyset(kf, setting, totalCount)
+	}
+	return fetchOffset(fetcher, setting, totalCount)
+}
+
+func (s *Setting) first() int {
+	if s.First != nil {
+		return *s.First
+	}
+	if s.Last != nil {
+		return -1
+	}
+	if s.DefaultFirst != 0 {
+		return s.DefaultFirst
+	}
+	return 10
+}
+
+func fetchOffset(fetcher pagination.PageFetcher, setting *Setting, totalCount int) (*Connection, error) {
+	start, end := 0, totalCount
+	if setting.After != nil {
+		after, err := ParseOffsetCursor(*setting.After)
+		if err != nil {
+			return nil, err
+		}
+		start = after + 1
+	}
+	if setting.Before != nil {
+		before, err := ParseOffsetCursor(*setting.Before)
+		if err != nil {
+			return nil, err
+		}
+		end = before
+	}
+	if start > totalCount {
+		start = totalCount
+	}
+	if end > totalCount {
+		end = totalCount
+	}
+	if end < start {
+		end = start
+	}
+
+	// first is applied before last, as Relay specification says
+	if first := setting.first(); first >= 0 && end > start+first {
+		end = start + first
+	}
+	if setting.Last != nil && start < end-*setting.Last {
+		start = end - *setting.Last
+	}
+
+	result := make(pagination.PageFetchResult, 0, end-start)
+	if end > start {
+		input := &pagination.PageFetchInput{
+			Limit:  end - start,
+			Offset: start,
+			Orders: setting.Orders,
+		}
+		if err := fetcher.FetchPage(setting.Cond, input, &result); err != nil {
+			return nil, &pagination.FetcherError{Stage: pagination.StageActive, Err: err}
+		}
+	}
+
+	edges := make([]*Edge, 0, len(result))
+	for i, node := range result {
+		edges = append(edges, &Edge{Cursor: OffsetCursor(start + i), Node: node})
+	}
+	return newConnection(edges, start > 0, start+len(result) < totalCount, totalCount), nil
+}
+
+func fetchKeyset(fetcher pagination.KeysetFetcher, setting *Setting, totalCount int) (*Connection, error) {
+	input := &pagination.KeysetInput{Orders: setting.Orders}
+	if setting.After != nil {
+		input.After = *setting.After
+	}
+	if setting.Before != nil {
+		input.Before = *setting.Before
+	}
+
+	// fetch one more record to know whether the next or previous page exists
+	var hasNext, hasPrev bool
+	result := make(pagination.PageFetchResult, 0)
+	if first := setting.first(); first >= 0 {
+		input.Limit = first + 1
+		if err := fetcher.FetchKeyset(setting.Cond, input, &result); err != nil {
+			return nil, &pagination.FetcherError{Stage: pagination.StageActive, Err: err}
+		}
+		hasPrev = input.After != ""
+		if len(result) > first {
+			result = result[:first]
+			hasNext = true
+		}
+		if setting.Last != nil && len(result) > *setting.Last {
+			result = result[len(result)-*setting.Last:]
+			hasPrev = true
+		}
+	} else {
+		last := *setting.Last
+		input.Limit = last + 1
+		input.FromEnd = true
+		if err := fetcher.FetchKeyset(setting.Cond, input, &result); err != nil {
+			return nil, &pagination.FetcherError{Stage: pagination.StageActive, Err: err}
+		}
+		hasNext = input.Before != ""
+		if len(result) > last {
+			result = result[len(result)-last:]
+			hasPrev = true
+		}
+	}
+
+	edges := make([]*Edge, 0, len(result))
+	for _, node := range result {
+		cursor, err := fetcher.Cursor(node)
+		if err != nil {
+			return nil, &pagination.FetcherError{Stage: pagination.StageActive, Err: err}
+		}
+		edges = append(edges, &Edge{Cursor: cursor, Node: node})
+	}
+	return newConnection(edges, hasPrev, hasNext, totalCount), nil
+}
+
+func newConnection(edges []*Edge, hasPrev, hasNext bool, totalCount int) *Connection {
+	conn := &Connection{
+		Edges: edges,
+		PageInfo: PageInfo{
+			HasNextPage:     hasNext,
+			HasPreviousPage: hasPrev,
+		},
+		TotalCount: totalCount,
+	}
+	if len(edges) > 0 {
+		conn.PageInfo.StartCursor = &edges[0].Cursor
+		conn.PageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+	return conn
+}
@@ -0,0 +1,256 @@
+package relay_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/relay"
+)
+
+type numberFetcher struct {
+	numbers []int
+}
+
+func newNumberFetcher(count int) *numberFetcher {
+	nf := &numberFetcher{}
+	for i := 1; i <= count; i++ {
+		nf.numbers = append(nf.numbers, i)
+	}
+	return nf
+}
+
+func (nf *numberFetcher) Count(cond interface{}) (int, error) {
+	return len(nf.numbers), nil
+}
+
+func (nf *numberFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	toIndex := input.Offset + input.Limit
+	if toIndex > len(nf.numbers) {
+		toIndex = len(nf.numbers)
+	}
+	for _, n := range nf.numbers[input.Offset:toIndex] {
+		*result = append(*result, n)
+	}
+	return nil
+}
+
+// keysetNumberFetcher uses numbers themselves as cursors.
+type keysetNumberFetcher struct {
+	numberFetcher
+}
+
+func (kf *keysetNumberFetcher) Cursor(node interface{}) (string, error) {
+	return strconv.Itoa(node.(int)), nil
+}
+
+func (kf *keysetNumberFetcher) FetchKeyset(cond interface{}, input *pagination.KeysetInput, result *pagination.PageFetchResult) error {
+	matched := []int{}
+	for _, n := range kf.numbers {
+		if input.After != "" {
+			if after, _ := strconv.Atoi(input.After); n <= after {
+				continue
+			}
+		}
+		if input.Before != "" {
+			if before, _ := strconv.Atoi(input.Before); n >= before {
+				continue
+			}
+		}
+		matched = append(matched, n)
+	}
+	if len(matched) > input.Limit {
+		if input.FromEnd {
+			matched = matched[len(matched)-input.Limit:]
+		} else {
+			matched = matched[:input.Limit]
+		}
+	}
+	for _, n := range matched {
+		*result = append(*result, n)
+	}
+	return nil
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func nodes(conn *relay.Connection) []int {
+	ns := []int{}
+	for _, e := range conn.Edges {
+		ns = append(ns, e.Node.(int))
+	}
+	return ns
+}
+
+func TestOffsetCursor(t *testing.T) {
+	// same as graphql-relay-js
+	if got := relay.OffsetCursor(3); got != "YXJyYXljb25uZWN0aW9uOjM=" {
+		t.Errorf("OffsetCursor() = %v, want %v", got, "YXJyYXljb25uZWN0aW9uOjM=")
+	}
+	if got, err := relay.ParseOffsetCursor("YXJyYXljb25uZWN0aW9uOjM="); got != 3 || err != nil {
+		t.Errorf("ParseOffsetCursor() = %v, %v, want 3", got, err)
+	}
+	for _, cursor := range []string{"!", "Zm9vOjM=", "YXJyYXljb25uZWN0aW9uOi0x"} {
+		if _, err := relay.ParseOffsetCursor(cursor); err != relay.ErrInvalidCursor {
+			t.Errorf("ParseOffsetCursor(%q) error = %v, want %v", cursor, err, relay.ErrInvalidCursor)
+		}
+	}
+}
+
+func TestFetch_Offset(t *testing.T) {
+	tests := []struct {
+		name        string
+		setting     *relay.Setting
+		wantNodes   []int
+		wantHasNext bool
+		wantHasPrev bool
+		wantErr     bool
+	}{
+		{"default first", &relay.Setting{DefaultFirst: 3}, []int{1, 2, 3}, true, false, false},
+		{"first", &relay.Setting{First: intPtr(4)}, []int{1, 2, 3, 4}, true, false, false},
+		{"first after", &relay.Setting{First: intPtr(3), After: strPtr(relay.OffsetCursor(2))}, []int{4, 5, 6}, true, true, false},
+		{"first after reaches the end", &relay.Setting{First: intPtr(5), After: strPtr(relay.OffsetCursor(7))}, []int{9, 10}, false, true, false},
+		{"last", &relay.Setting{Last: intPtr(3)}, []int{8, 9, 10}, false, true, false},
+		{"last before", &relay.Setting{Last: intPtr(3), Before: strPtr(relay.OffsetCursor(5))}, []int{3, 4, 5}, true, true, false},
+		{"last before reaches the start", &relay.Setting{Last: intPtr(3), Before: strPtr(relay.OffsetCursor(1))}, []int{1}, true, false, false},
+		{"first and last", &relay.Setting{First: intPtr(5), Last: intPtr(2)}, []int{4, 5}, true, true, false},
+		{"after the end", &relay.Setting{First: intPtr(3), After: strPtr(relay.OffsetCursor(20))}, []int{}, false, true, false},
+		{"first=0", &relay.Setting{First: intPtr(0)}, []int{}, true, false, false},
+		{"invalid cursor", &relay.Setting{After: strPtr("!")}, nil, false, false, true},
+		{"negative first", &relay.Setting{First: intPtr(-1)}, nil, false, false, true},
+		{"negative last", &relay.Setting{Last: intPtr(-1)}, nil, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := relay.Fetch(newNumberFetcher(10), tt.setting)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(nodes(got), tt.wantNodes) {
+				t.Errorf("Fetch() nodes = %v, want %v", nodes(got), tt.wantNodes)
+			}
+			if got.PageInfo.HasNextPage != tt.wantHasNext {
+				t.Errorf("Fetch() hasNextPage = %v, want %v", got.PageInfo.HasNextPage, tt.wantHasNext)
+			}
+			if got.PageInfo.HasPreviousPage != tt.wantHasPrev {
+				t.Errorf("Fetch() hasPreviousPage = %v, want %v", got.PageInfo.HasPreviousPage, tt.wantHasPrev)
+			}
+			if got.TotalCount != 10 {
+				t.Errorf("Fetch() totalCount = %v, want 10", got.TotalCount)
+			}
+			for _, e := range got.Edges {
+				if offset, _ := relay.ParseOffsetCursor(e.Cursor); offset != e.Node.(int)-1 {
+					t.Errorf("Fetch() cursor of %v = offset %v, want %v", e.Node, offset, e.Node.(int)-1)
+				}
+			}
+			if len(got.Edges) == 0 {
+				if got.PageInfo.StartCursor != nil || got.PageInfo.EndCursor != nil {
+					t.Errorf("Fetch() cursors of empty connection must be nil, got %+v", got.PageInfo)
+				}
+				return
+			}
+			if *got.PageInfo.StartCursor != got.Edges[0].Cursor || *got.PageInfo.EndCursor != got.Edges[len(got.Edges)-1].Cursor {
+				t.Errorf("Fetch() startCursor, endCursor = %v, %v", *got.PageInfo.StartCursor, *got.PageInfo.EndCursor)
+			}
+		})
+	}
+}
+
+func TestFetch_Keyset(t *testing.T) {
+	tests := []struct {
+		name        string
+		setting     *relay.Setting
+		wantNodes   []int
+		wantHasNext bool
+		wantHasPrev bool
+	}{
+		{"default first", &relay.Setting{}, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, false, false},
+		{"first", &relay.Setting{First: intPtr(3)}, []int{1, 2, 3}, true, false},
+		{"first after", &relay.Setting{First: intPtr(3), After: strPtr("3")}, []int{4, 5, 6}, true, true},
+		{"first after reaches the end", &relay.Setting{First: intPtr(3), After: strPtr("8")}, []int{9, 10}, false, true},
+		{"last", &relay.Setting{Last: intPtr(3)}, []int{8, 9, 10}, false, true},
+		{"last before", &relay.Setting{Last: intPtr(3), Before: strPtr("6")}, []int{3, 4, 5}, true, true},
+		{"last before reaches the start", &relay.Setting{Last: intPtr(3), Before: strPtr("3")}, []int{1, 2}, true, false},
+		{"first and last", &relay.Setting{First: intPtr(5), Last: intPtr(2)}, []int{4, 5}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := &keysetNumberFetcher{*newNumberFetcher(10)}
+			got, err := relay.Fetch(fetcher, tt.setting)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if !reflect.DeepEqual(nodes(got), tt.wantNodes) {
+				t.Errorf("Fetch() nodes = %v, want %v", nodes(got), tt.wantNodes)
+			}
+			if got.PageInfo.HasNextPage != tt.wantHasNext {
+				t.Errorf("Fetch() hasNextPage = %v, want %v", got.PageInfo.HasNextPage, tt.wantHasNext)
+			}
+			if got.PageInfo.HasPreviousPage != tt.wantHasPrev {
+				t.Errorf("Fetch() hasPreviousPage = %v, want %v", got.PageInfo.HasPreviousPage, tt.wantHasPrev)
+			}
+			if got.TotalCount != 10 {
+				t.Errorf("Fetch() totalCount = %v, want 10", got.TotalCount)
+			}
+			for _, e := range got.Edges {
+				if e.Cursor != strconv.Itoa(e.Node.(int)) {
+					t.Errorf("Fetch() cursor of %v = %v", e.Node, e.Cursor)
+				}
+			}
+		})
+	}
+}
+
+func TestFetch_DecoratedKeyset(t *testing.T) {
+	fetcher := pagination.DecorateFetcher(&keysetNumberFetcher{*newNumberFetcher(10)},
+		pagination.WithRetry(pagination.ConstantBackoff(time.Millisecond, 1)),
+	)
+	// the keyset cursor is accepted through the middleware, instead of failing as an offset cursor
+	got, err := relay.Fetch(fetcher, &relay.Setting{First: intPtr(3), After: strPtr("3")})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if want := []int{4, 5, 6}; !reflect.DeepEqual(nodes(got), want) {
+		t.Errorf("Fetch() nodes = %v, want %v", nodes(got), want)
+	}
+	if got.PageInfo.EndCursor == nil || *got.PageInfo.EndCursor != "6" {
+		t.Errorf("Fetch() endCursor = %v, want the keyset cursor 6", got.PageInfo.EndCursor)
+	}
+}
+
+func TestFetch_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting *relay.Setting
+		want    error
+	}{
+		{"negative first", &relay.Setting{First: intPtr(-1)}, pagination.ErrInvalidLimit},
+		{"negative last", &relay.Setting{Last: intPtr(-1)}, pagination.ErrInvalidLimit},
+		{"invalid after", &relay.Setting{After: strPtr("!")}, relay.ErrInvalidCursor},
+		{"invalid before", &relay.Setting{Before: strPtr("Zm9vOjM=")}, relay.ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := relay.Fetch(newNumberFetcher(10), tt.setting)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Fetch() error = %v, want %v", err, tt.want)
+			}
+			if got := pagination.HTTPStatus(err); got != 400 {
+				t.Errorf("HTTPStatus() = %v, want 400", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Setting returns the setting of Fetch for the query parameters.
+func (q *Query) Setting(cond interface{}) *Setting {
+	return &Setting{
+		Limit:  q.Limit,
+		Page:   q.Page,
+		Offset: q.Offset,
+		Cond:   cond,
+		Orders: q.Sort,
+	}
+}
+
+// WriteResponse writes the result of Fetch as JSON, with X-Total-Count and X-Total-Pages headers.
+func WriteResponse(w http.ResponseWriter, totalCount, pageCount int, res *PagingResponse) error {
+	body, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(totalCount))
+	w.Header().Set("X-Total-Pages", strconv.Itoa(pageCount))
+	w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count,X-Total-Pages")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(body)
+	return err
+}
+
+// WriteError writes the error as a JSON message, with the status code of HTTPStatus.
+// The message of a server error (5xx) is the status text, so errors of the fetcher and the database are not exposed to clients.
+func WriteError(w http.ResponseWriter, err error) error {
+	status := HTTPStatus(err)
+	message := err.Error()
+	if status >= http.StatusInternalServerError {
+		message = http.StatusText(status)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(map[string]string{"message": message})
+}
@@ -0,0 +1,75 @@
+package pagination_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+func TestQuery_Setting(t *testing.T) {
+	q := pagination.ParseQuery("/fruits?limit=5&offset=12&sort=-price")
+	got := q.Setting(100)
+	want := &pagination.Setting{
+		Limit:  5,
+		Page:   1,
+		Offset: 12,
+		Cond:   100,
+		Orders: []*pagination.Order{{Direction: pagination.DirectionDesc, ColumnName: "price"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Setting() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	_, _, res, err := pagination.Fetch(newFruitFetcher(), &pagination.Setting{Limit: 10, Page: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	if err := pagination.WriteResponse(w, 11, 2, res); err != nil {
+		t.Fatalf("WriteResponse() error = %v", err)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("WriteResponse() status = %v", w.Code)
+	}
+	wantHeader := http.Header{
+		"X-Total-Count":                 {"11"},
+		"X-Total-Pages":                 {"2"},
+		"Access-Control-Expose-Headers": {"X-Total-Count,X-Total-Pages"},
+		"Content-Type":                  {"application/json; charset=utf-8"},
+	}
+	if !reflect.DeepEqual(w.Header(), wantHeader) {
+		t.Errorf("WriteResponse() header = %v, want %v", w.Header(), wantHeader)
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"invalid page", pagination.ErrInvalidPage, http.StatusBadRequest, `{"message":"page must be >= 1"}` + "\n"},
+		{"out of range", &pagination.OutOfRangeError{Page: 3, PageCount: 2}, http.StatusNotFound, `{"message":"page is out of range. page range is 1-2"}` + "\n"},
+		{"fetcher", &pagination.FetcherError{Stage: pagination.StageCount, Err: errors.New("a > b")}, http.StatusInternalServerError, `{"message":"Internal Server Error"}` + "\n"},
+		{"timeout", fmt.Errorf("%w after 1s", pagination.ErrTimeout), http.StatusGatewayTimeout, `{"message":"Gateway Timeout"}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := pagination.WriteError(w, tt.err); err != nil {
+				t.Fatalf("WriteError() error = %v", err)
+			}
+			if w.Code != tt.wantStatus || w.Body.String() != tt.wantBody {
+				t.Errorf("WriteError() = %v %q, want %v %q", w.Code, w.Body.String(), tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+}
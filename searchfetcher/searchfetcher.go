@@ -0,0 +1,289 @@
+// Package searchfetcher provides pagination.PageFetcher of Elasticsearch and OpenSearch.
+package searchfetcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// Fetcher is pagination.PageFetcher of the search API.
+// It also implements pagination.LowerBoundPageFetcher, since hits beyond TrackTotalHits are not counted.
+// Pages beyond MaxResultWindow are fetched in the reversed sort when they are near the last hit,
+// and with search_after from the first hit otherwise, which costs a request per MaxResultWindow hits before the page.
+type Fetcher struct {
+	// URL is the search API of the index, e.g. http://localhost:9200/fruits/_search.
+	URL string
+	// Client is used for requests. http.DefaultClient is used if nil.
+	Client *http.Client
+	// Query maps the condition to the query of the request body. match_all is used if nil.
+	Query func(cond interface{}) map[string]interface{}
+	// Fields maps column names of pagination.Order to fields of the index.
+	// Orders of other columns are rejected.
+	Fields map[string]string
+	// TieBreaker is the unique field, which is appended to the sort for search_after. It is required.
+	// Sorting on _id is disabled by default since Elasticsearch 8, so it should be a keyword or numeric field of documents.
+	TieBreaker string
+	// TrackTotalHits is track_total_hits of the count. Counts above it are lower bounds. 10000 is used if zero.
+	TrackTotalHits int
+	// MaxResultWindow is index.max_result_window of the index. 10000 is used if zero.
+	MaxResultWindow int
+	// Decode decodes _source of a hit into a record. map[string]interface{} is the record if nil.
+	Decode func(source json.RawMessage) (interface{}, error)
+}
+
+// searchRequest is the request body of the search API.
+type searchRequest struct {
+	Query          map[string]interface{}   `json:"query"`
+	Sort           []map[string]interface{} `json:"sort,omitempty"`
+	From           int                      `json:"from,omitempty"`
+	Size           int                      `json:"size"`
+	SearchAfter    []interface{}            `json:"search_after,omitempty"`
+	TrackTotalHits interface{}              `json:"track_total_hits"`
+	Source         *bool                    `json:"_source,omitempty"`
+}
+
+// searchResponse is the response body of the search API.
+type searchResponse struct {
+	Hits struct {
+		Total struct {
+			Value    int    `json:"value"`
+			Relation string `json:"relation"`
+		} `json:"total"`
+		Hits []searchHit `json:"hits"`
+	} `json:"hits"`
+}
+
+// searchHit is a hit of the search API. Numbers of Sort are json.Number, so long values keep their precision for search_after.
+type searchHit struct {
+	Source json.RawMessage `json:"_source"`
+	Sort   []interface{}   `json:"sort"`
+}
+
+// Count counts hits of the condition up to TrackTotalHits.
+func (f *Fetcher) Count(cond interface{}) (int, error) {
+	count, _, err := f.CountLowerBound(cond)
+	return count, err
+}
+
+// CountLowerBound counts hits of the condition up to TrackTotalHits, and reports whether there are more.
+func (f *Fetcher) CountLowerBound(cond interface{}) (int, bool, error) {
+	res, err := f.search(&searchRequest{
+		Query:          f.query(cond),
+		Size:           0,
+		TrackTotalHits: f.trackTotalHits(),
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return res.Hits.Total.Value, res.Hits.Total.Relation == "gte", nil
+}
+
+// FetchPage fetches hits of the condition in the range of input.
+func (f *Fetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	sort, err := f.sort(input.Orders)
+	if err != nil {
+		return err
+	}
+	req := &searchRequest{
+		Query:          f.query(cond),
+		Sort:           sort,
+		From:           input.Offset,
+		Size:           input.Limit,
+		TrackTotalHits: false,
+	}
+
+	var hits []searchHit
+	if input.Offset+input.Limit > f.maxResultWindow() {
+		hits, err = f.searchDeep(req, input)
+	} else {
+		var res *searchResponse
+		res, err = f.search(req)
+		if res != nil {
+			hits = res.Hits.Hits
+		}
+	}
+	if err != nil {
+		return err
+	}
+	for _, hit := range hits {
+		record, err := f.decode(hit.Source)
+		if err != nil {
+			return err
+		}
+		*result = append(*result, record)
+	}
+	return nil
+}
+
+// searchDeep searches hits of a page beyond MaxResultWindow.
+// Hits are counted up to the window after the offset first, and the page is searched in the reversed sort if it is within the window from the last hit.
+// Otherwise hits before the offset are skipped with search_after.
+func (f *Fetcher) searchDeep(req *searchRequest, input *pagination.PageFetchInput) ([]searchHit, error) {
+	count, err := f.search(&searchRequest{
+		Query:          req.Query,
+		Size:           0,
+		TrackTotalHits: input.Offset + f.maxResultWindow(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total := count.Hits.Total.Value
+	if total <= input.Offset {
+		return nil, nil
+	}
+	if rest := total - input.Offset; count.Hits.Total.Relation != "gte" && rest <= f.maxResultWindow() {
+		size := input.Limit
+		if size > rest {
+			size = rest
+		}
+		res, err := f.search(&searchRequest{
+			Query:          req.Query,
+			Sort:           reverseSort(req.Sort),
+			From:           rest - size,
+			Size:           size,
+			TrackTotalHits: false,
+		})
+		if err != nil {
+			return nil, err
+		}
+		hits := res.Hits.Hits
+		for i, j := 0, len(hits)-1; i < j; i, j = i+1, j-1 {
+			hits[i], hits[j] = hits[j], hits[i]
+		}
+		return hits, nil
+	}
+
+	// skip hits before the offset with search_after, fetching only their sort values
+	noSource := false
+	skip := &searchRequest{Query: req.Query, Sort: req.Sort, TrackTotalHits: false, Source: &noSource}
+	for skipped := 0; skipped < input.Offset; {
+		skip.Size = input.Offset - skipped
+		if skip.Size > f.maxResultWindow() {
+			skip.Size = f.maxResultWindow()
+		}
+		res, err := f.search(skip)
+		if err != nil {
+			return nil, err
+		}
+		hits := res.Hits.Hits
+		if len(hits) == 0 {
+			return nil, nil
+		}
+		skip.SearchAfter = hits[len(hits)-1].Sort
+		skipped += len(hits)
+	}
+	req.From = 0
+	req.SearchAfter = skip.SearchAfter
+	res, err := f.search(req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Hits.Hits, nil
+}
+
+func (f *Fetcher) search(req *searchRequest) (*searchResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(f.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("searchfetcher: %s: %s", resp.Status, b)
+	}
+	res := &searchResponse{}
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err := dec.Decode(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (f *Fetcher) query(cond interface{}) map[string]interface{} {
+	if f.Query == nil {
+		return map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+	return f.Query(cond)
+}
+
+// sort returns the sort of the orders, with the tie breaker at last.
+func (f *Fetcher) sort(orders []*pagination.Order) ([]map[string]interface{}, error) {
+	tieBreaker := f.TieBreaker
+	if tieBreaker == "" {
+		return nil, errors.New("searchfetcher: TieBreaker is required")
+	}
+
+	sort := make([]map[string]interface{}, 0, len(orders)+1)
+	hasTieBreaker := false
+	for _, o := range orders {
+		field, ok := f.Fields[o.ColumnName]
+		if !ok {
+			return nil, fmt.Errorf("searchfetcher: unknown sort column %q", o.ColumnName)
+		}
+		order := "asc"
+		if o.Direction == pagination.DirectionDesc {
+			order = "desc"
+		}
+		sort = append(sort, map[string]interface{}{field: map[string]interface{}{"order": order}})
+		hasTieBreaker = hasTieBreaker || field == tieBreaker
+	}
+	if !hasTieBreaker {
+		sort = append(sort, map[string]interface{}{tieBreaker: map[string]interface{}{"order": "asc"}})
+	}
+	return sort, nil
+}
+
+// reverseSort returns the sort in the reversed order.
+// Missing values are sorted last in both orders by default, so they are put first explicitly.
+func reverseSort(sort []map[string]interface{}) []map[string]interface{} {
+	reversed := make([]map[string]interface{}, 0, len(sort))
+	for _, s := range sort {
+		for field, opts := range s {
+			order := "desc"
+			if opts.(map[string]interface{})["order"] == "desc" {
+				order = "asc"
+			}
+			reversed = append(reversed, map[string]interface{}{field: map[string]interface{}{"order": order, "missing": "_first"}})
+		}
+	}
+	return reversed
+}
+
+func (f *Fetcher) decode(source json.RawMessage) (interface{}, error) {
+	if f.Decode != nil {
+		return f.Decode(source)
+	}
+	record := map[string]interface{}{}
+	err := json.Unmarshal(source, &record)
+	return record, err
+}
+
+func (f *Fetcher) trackTotalHits() int {
+	if f.TrackTotalHits == 0 {
+		return 10000
+	}
+	return f.TrackTotalHits
+}
+
+func (f *Fetcher) maxResultWindow() int {
+	if f.MaxResultWindow == 0 {
+		return 10000
+	}
+	return f.MaxResultWindow
+}
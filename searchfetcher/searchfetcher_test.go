@@ -0,0 +1,339 @@
+package searchfetcher_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/searchfetcher"
+)
+
+type fruit struct {
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+}
+
+var dummyFruits = []fruit{
+	{"Apple", 112},
+	{"Pear", 245},
+	{"Banana", 60},
+	{"Orange", 80},
+	{"Kiwi", 106},
+	{"Strawberry", 350},
+	{"Grape", 400},
+	{"Grapefruit", 150},
+	{"Pineapple", 200},
+	{"Cherry", 140},
+	{"Mango", 199},
+}
+
+// searchServer is a stand-in of the search API, which supports match_all and range queries,
+// sort, from and size up to the max result window, search_after and track_total_hits.
+// Documents have the id field of long values beyond the precision of float64.
+type searchServer struct {
+	maxResultWindow int
+	mu              sync.Mutex
+	requests        []string
+}
+
+func (s *searchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query          map[string]map[string]map[string]float64 `json:"query"`
+		Sort           []map[string]map[string]string           `json:"sort"`
+		From           int                                      `json:"from"`
+		Size           int                                      `json:"size"`
+		SearchAfter    []interface{}                            `json:"search_after"`
+		TrackTotalHits interface{}                              `json:"track_total_hits"`
+		Source         *bool                                    `json:"_source"`
+	}
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, fmt.Sprintf("from=%d size=%d search_after=%v track_total_hits=%v", req.From, req.Size, req.SearchAfter, req.TrackTotalHits))
+	s.mu.Unlock()
+
+	if req.From+req.Size > s.maxResultWindow {
+		http.Error(w, `{"error":"Result window is too large"}`, http.StatusBadRequest)
+		return
+	}
+
+	type doc struct {
+		id     int64
+		source fruit
+	}
+	docs := []doc{}
+	for i, f := range dummyFruits {
+		if rng, ok := req.Query["range"]["price"]; ok && float64(f.Price) < rng["gte"] {
+			continue
+		}
+		docs = append(docs, doc{1<<53 + int64(i+1), f})
+	}
+	sortValues := func(d doc) []interface{} {
+		values := []interface{}{}
+		for _, s := range req.Sort {
+			for field := range s {
+				switch field {
+				case "price":
+					values = append(values, float64(d.source.Price))
+				case "name":
+					values = append(values, d.source.Name)
+				case "id":
+					values = append(values, d.id)
+				}
+			}
+		}
+		return values
+	}
+	// compare returns the order of sort values a and b
+	compare := func(a, b []interface{}) int {
+		for i, s := range req.Sort {
+			c := 0
+			switch x := a[i].(type) {
+			case float64:
+				y, _ := strconv.ParseFloat(fmt.Sprint(b[i]), 64)
+				if x < y {
+					c = -1
+				} else if x > y {
+					c = 1
+				}
+			case string:
+				c = strings.Compare(x, b[i].(string))
+			case int64:
+				y, err := strconv.ParseInt(fmt.Sprint(b[i]), 10, 64)
+				if err != nil {
+					panic(err)
+				}
+				if x < y {
+					c = -1
+				} else if x > y {
+					c = 1
+				}
+			}
+			for _, o := range s {
+				if o["order"] == "desc" {
+					c = -c
+				}
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+	sort.SliceStable(docs, func(i, j int) bool { return compare(sortValues(docs[i]), sortValues(docs[j])) < 0 })
+
+	total := len(docs)
+	if req.SearchAfter != nil {
+		after := []doc{}
+		for _, d := range docs {
+			if compare(sortValues(d), req.SearchAfter) > 0 {
+				after = append(after, d)
+			}
+		}
+		docs = after
+	}
+	if req.From > len(docs) {
+		req.From = len(docs)
+	}
+	docs = docs[req.From:]
+	if req.Size < len(docs) {
+		docs = docs[:req.Size]
+	}
+
+	res := map[string]interface{}{}
+	hits := []map[string]interface{}{}
+	for _, d := range docs {
+		hit := map[string]interface{}{"_id": d.id, "sort": sortValues(d)}
+		if req.Source == nil || *req.Source {
+			hit["_source"] = d.source
+		}
+		hits = append(hits, hit)
+	}
+	relation := "eq"
+	if limit, err := strconv.Atoi(fmt.Sprint(req.TrackTotalHits)); err == nil && total > limit {
+		total, relation = limit, "gte"
+	}
+	res["hits"] = map[string]interface{}{
+		"total": map[string]interface{}{"value": total, "relation": relation},
+		"hits":  hits,
+	}
+	json.NewEncoder(w).Encode(res)
+}
+
+func newFruitFetcher(url string) *searchfetcher.Fetcher {
+	return &searchfetcher.Fetcher{
+		URL: url,
+		Query: func(cond interface{}) map[string]interface{} {
+			if low, ok := cond.(int); ok {
+				return map[string]interface{}{"range": map[string]interface{}{"price": map[string]interface{}{"gte": low}}}
+			}
+			return nil
+		},
+		Fields:     map[string]string{"name": "name", "price": "price"},
+		TieBreaker: "id",
+		Decode: func(source json.RawMessage) (interface{}, error) {
+			var f fruit
+			err := json.Unmarshal(source, &f)
+			return f, err
+		},
+	}
+}
+
+func names(records pagination.PageFetchResult) []string {
+	names := []string{}
+	for _, r := range records {
+		names = append(names, r.(fruit).Name)
+	}
+	return names
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	server := &searchServer{maxResultWindow: 10000}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	totalCount, pageCount, res, err := pagination.Fetch(newFruitFetcher(ts.URL), &pagination.Setting{
+		Limit:  2,
+		Page:   2,
+		Cond:   100,
+		Orders: []*pagination.Order{{Direction: pagination.DirectionDesc, ColumnName: "price"}},
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if totalCount != 9 || pageCount != 5 || res.TotalLowerBound {
+		t.Errorf("Fetch() = %v, %v, %v, want 9, 5, false", totalCount, pageCount, res.TotalLowerBound)
+	}
+	want := map[string][]string{
+		"first":  {"Grape", "Strawberry"},
+		"active": {"Pear", "Pineapple"},
+		"last":   {"Kiwi"},
+	}
+	for name, want := range want {
+		if got := names(res.Pages[name]); !reflect.DeepEqual(got, want) {
+			t.Errorf("Fetch() pages.%v = %v, want %v", name, got, want)
+		}
+	}
+	wantRequests := []string{
+		"from=0 size=0 search_after=[] track_total_hits=10000",
+		"from=0 size=9 search_after=[] track_total_hits=false",
+	}
+	if !reflect.DeepEqual(server.requests, wantRequests) {
+		t.Errorf("Fetch() requests =\n%v\nwant\n%v", strings.Join(server.requests, "\n"), strings.Join(wantRequests, "\n"))
+	}
+}
+
+func TestFetcher_LowerBound(t *testing.T) {
+	ts := httptest.NewServer(&searchServer{maxResultWindow: 10000})
+	defer ts.Close()
+
+	fetcher := newFruitFetcher(ts.URL)
+	fetcher.TrackTotalHits = 8
+	totalCount, pageCount, res, err := pagination.Fetch(fetcher, &pagination.Setting{Limit: 3, Page: 1})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if totalCount != 8 || pageCount != 3 || !res.TotalLowerBound {
+		t.Errorf("Fetch() = %v, %v, %v, want 8, 3, true", totalCount, pageCount, res.TotalLowerBound)
+	}
+}
+
+func TestFetcher_SearchAfter(t *testing.T) {
+	byName := []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: "name"}}
+	tests := []struct {
+		name         string
+		input        *pagination.PageFetchInput
+		want         []string
+		wantRequests []string
+	}{
+		{
+			"near the last hit",
+			&pagination.PageFetchInput{Limit: 3, Offset: 7, Orders: byName},
+			[]string{"Orange", "Pear", "Pineapple"},
+			[]string{
+				"from=0 size=0 search_after=[] track_total_hits=11",
+				"from=1 size=3 search_after=[] track_total_hits=false",
+			},
+		},
+		{
+			"last page",
+			&pagination.PageFetchInput{Limit: 3, Offset: 9, Orders: byName},
+			[]string{"Pineapple", "Strawberry"},
+			[]string{
+				"from=0 size=0 search_after=[] track_total_hits=13",
+				"from=0 size=2 search_after=[] track_total_hits=false",
+			},
+		},
+		{
+			// search_after of long values is exact
+			"far from the last hit",
+			&pagination.PageFetchInput{Limit: 3, Offset: 5},
+			[]string{"Strawberry", "Grape", "Grapefruit"},
+			[]string{
+				"from=0 size=0 search_after=[] track_total_hits=9",
+				"from=0 size=4 search_after=[] track_total_hits=false",
+				"from=0 size=1 search_after=[9007199254740996] track_total_hits=false",
+				"from=0 size=3 search_after=[9007199254740997] track_total_hits=false",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &searchServer{maxResultWindow: 4}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			fetcher := newFruitFetcher(ts.URL)
+			fetcher.MaxResultWindow = 4
+			result := pagination.PageFetchResult{}
+			if err := fetcher.FetchPage(nil, tt.input, &result); err != nil {
+				t.Fatalf("FetchPage() error = %v", err)
+			}
+			if got := names(result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchPage() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(server.requests, tt.wantRequests) {
+				t.Errorf("FetchPage() requests =\n%v\nwant\n%v", strings.Join(server.requests, "\n"), strings.Join(tt.wantRequests, "\n"))
+			}
+		})
+	}
+}
+
+func TestFetcher_Errors(t *testing.T) {
+	ts := httptest.NewServer(&searchServer{maxResultWindow: 10000})
+	defer ts.Close()
+	fetcher := newFruitFetcher(ts.URL)
+
+	err := fetcher.FetchPage(nil, &pagination.PageFetchInput{
+		Limit:  2,
+		Orders: []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: "secret"}},
+	}, &pagination.PageFetchResult{})
+	if err == nil || !strings.Contains(err.Error(), "unknown sort column") {
+		t.Errorf("FetchPage() error = %v, want unknown sort column", err)
+	}
+
+	fetcher.TieBreaker = ""
+	err = fetcher.FetchPage(nil, &pagination.PageFetchInput{Limit: 2}, &pagination.PageFetchResult{})
+	if err == nil || !strings.Contains(err.Error(), "TieBreaker is required") {
+		t.Errorf("FetchPage() error = %v, want TieBreaker is required", err)
+	}
+	fetcher.TieBreaker = "id"
+
+	fetcher.MaxResultWindow = 20000
+	err = fetcher.FetchPage(nil, &pagination.PageFetchInput{Limit: 2, Offset: 10000}, &pagination.PageFetchResult{})
+	if err == nil || !strings.Contains(err.Error(), "400 Bad Request") {
+		t.Errorf("FetchPage() error = %v, want 400 Bad Request", err)
+	}
+}
@@ -0,0 +1,42 @@
+//go:build go1.21
+// +build go1.21
+
+package pagination
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SlogHooks returns hooks which log Count and FetchPage with the logger.
+// Successful calls are logged at debug level, and errors are logged at error level.
+// Records are logged with the context of FetchContext, so handlers can add request attributes.
+func SlogHooks(logger *slog.Logger) *Hooks {
+	return &Hooks{
+		AfterCount: func(ctx context.Context, cond interface{}, count int, elapsed time.Duration) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "pagination count",
+				slog.String("stage", string(StageCount)),
+				slog.Int("count", count),
+				slog.Duration("elapsed", elapsed),
+			)
+		},
+		AfterFetch: func(ctx context.Context, stage Stage, input *PageFetchInput, rows int, elapsed time.Duration) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "pagination fetch",
+				slog.String("stage", string(stage)),
+				slog.Int("limit", input.Limit),
+				slog.Int("offset", input.Offset),
+				slog.Int("rows", rows),
+				slog.Duration("elapsed", elapsed),
+			)
+		},
+		OnError: func(ctx context.Context, stage Stage, input *PageFetchInput, err error, elapsed time.Duration) {
+			attrs := []slog.Attr{slog.String("stage", string(stage))}
+			if input != nil {
+				attrs = append(attrs, slog.Int("limit", input.Limit), slog.Int("offset", input.Offset))
+			}
+			attrs = append(attrs, slog.Duration("elapsed", elapsed), slog.String("error", err.Error()))
+			logger.LogAttrs(ctx, slog.LevelError, "pagination fetch failed", attrs...)
+		},
+	}
+}
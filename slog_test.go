@@ -0,0 +1,42 @@
+//go:build go1.21
+// +build go1.21
+
+package pagination_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+func TestSlogHooks(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "elapsed" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	pagination.Fetch(&failingFetcher{successes: 1}, &pagination.Setting{
+		Limit: 10,
+		Page:  5,
+		Hooks: pagination.SlogHooks(logger),
+	})
+
+	want := []string{
+		`level=DEBUG msg="pagination count" stage=count count=103`,
+		`level=DEBUG msg="pagination fetch" stage=active limit=50 offset=20 rows=50`,
+		`level=ERROR msg="pagination fetch failed" stage=first limit=10 offset=0 error="connection reset"`,
+	}
+	got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("SlogHooks() logs =\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
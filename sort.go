@@ -29,10 +29,12 @@ func ParseSort(queryStr string) []*Order {
 	}
 	query := u.Query()
 
-	if s := query.Get("sort"); s != "" {
-		return ParseOrders(s)
+	// repeated sort parameters are joined in order
+	orders := []*Order{}
+	for _, s := range query["sort"] {
+		orders = append(orders, ParseOrders(s)...)
 	}
-	return []*Order{}
+	return orders
 }
 
 // ParseOrders parses sort option string
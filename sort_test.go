@@ -23,6 +23,10 @@ func TestParseSort(t *testing.T) {
 			&pagination.Order{Direction: pagination.DirectionAsc, ColumnName: "col_c"},
 			&pagination.Order{Direction: pagination.DirectionDesc, ColumnName: "col_d"},
 		}},
+		{"repeated sort", args{"?sort=-col_e&sort=+col_f"}, []*pagination.Order{
+			&pagination.Order{Direction: pagination.DirectionDesc, ColumnName: "col_e"},
+			&pagination.Order{Direction: pagination.DirectionAsc, ColumnName: "col_f"},
+		}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
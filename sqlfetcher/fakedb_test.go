@@ -0,0 +1,197 @@
+package sqlfetcher_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// fakeDB is an in-memory database/sql driver which returns fruits and records the statements.
+// It understands only "COUNT(*)", "COUNT(*) OVER()", "LIMIT n OFFSET m", UNION ALL of them and a price lower limit given as the first arg.
+// Members of UNION ALL with range and row numbers are returned in reverse order, as databases may not keep the order.
+type fakeDB struct {
+	mu     sync.Mutex
+	fruits []fruit
+	log    []string
+	// queryErr fails queries which contain the string
+	queryErr string
+}
+
+type fruit struct {
+	Name  string
+	Price int64
+}
+
+var (
+	fakeDBs   = map[string]*fakeDB{}
+	fakeDBsMu sync.Mutex
+)
+
+func init() {
+	sql.Register("sqlfetcher-fake", fakeDriver{})
+}
+
+func openFakeDB(name string, fruits []fruit) (*sql.DB, *fakeDB) {
+	fdb := &fakeDB{fruits: fruits}
+	fakeDBsMu.Lock()
+	fakeDBs[name] = fdb
+	fakeDBsMu.Unlock()
+	db, err := sql.Open("sqlfetcher-fake", name)
+	if err != nil {
+		panic(err)
+	}
+	return db, fdb
+}
+
+func (fdb *fakeDB) record(format string, args ...interface{}) {
+	fdb.mu.Lock()
+	defer fdb.mu.Unlock()
+	fdb.log = append(fdb.log, fmt.Sprintf(format, args...))
+}
+
+func (fdb *fakeDB) statements() []string {
+	fdb.mu.Lock()
+	defer fdb.mu.Unlock()
+	return append([]string{}, fdb.log...)
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDBsMu.Lock()
+	defer fakeDBsMu.Unlock()
+	return &fakeConn{db: fakeDBs[name]}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.db.record("BEGIN isolation=%v read_only=%v", sql.IsolationLevel(opts.Isolation), opts.ReadOnly)
+	return &fakeTx{db: c.db}, nil
+}
+
+var (
+	limitOffsetPattern = regexp.MustCompile(`LIMIT (\d+) OFFSET (\d+)`)
+)
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.record("%v %v", query, namedValues(args))
+	if c.db.queryErr != "" && strings.Contains(query, c.db.queryErr) {
+		return nil, errors.New("query failed")
+	}
+
+	fruits := []fruit{}
+	for _, f := range c.db.fruits {
+		if len(args) > 0 && f.Price < args[0].Value.(int64) {
+			continue
+		}
+		fruits = append(fruits, f)
+	}
+
+	if strings.HasPrefix(query, "SELECT COUNT(*)") {
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{int64(len(fruits))}}}, nil
+	}
+
+	rows := &fakeRows{columns: []string{"name", "price"}}
+	window := strings.Contains(query, "COUNT(*) OVER()")
+	if window {
+		rows.columns = append(rows.columns, "pagination_count")
+	}
+	ranged := strings.Contains(query, "pagination_row")
+	if ranged {
+		rows.columns = append(rows.columns, "pagination_range", "pagination_row")
+	}
+	ms := limitOffsetPattern.FindAllStringSubmatch(query, -1)
+	if ms == nil {
+		return nil, fmt.Errorf("LIMIT and OFFSET are required: %v", query)
+	}
+	// each LIMIT and OFFSET is a member of UNION ALL
+	members := make([][][]driver.Value, len(ms))
+	for m, match := range ms {
+		limit, _ := strconv.Atoi(match[1])
+		offset, _ := strconv.Atoi(match[2])
+		for i := offset; i < offset+limit && i < len(fruits); i++ {
+			values := []driver.Value{fruits[i].Name, fruits[i].Price}
+			if window {
+				values = append(values, int64(len(fruits)))
+			}
+			if ranged {
+				values = append(values, int64(m), int64(i+1))
+			}
+			members[m] = append(members[m], values)
+		}
+	}
+	for m := range members {
+		if ranged {
+			m = len(members) - 1 - m
+		}
+		rows.values = append(rows.values, members[m]...)
+	}
+	return rows, nil
+}
+
+func namedValues(args []driver.NamedValue) []interface{} {
+	values := []interface{}{}
+	for _, a := range args {
+		values = append(values, a.Value)
+	}
+	return values
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.db.record("COMMIT")
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.db.record("ROLLBACK")
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	index   int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.index >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.index])
+	r.index++
+	return nil
+}
@@ -0,0 +1,219 @@
+// Package sqlfetcher provides pagination.PageFetcher of database/sql.
+package sqlfetcher
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// Fetcher is pagination.PageFetcher of SQL database.
+// It also implements pagination.TxPageFetcher, so Count and FetchPage of a single Fetch run in one snapshot,
+// and pagination.MultiRangeFetcher.
+type Fetcher struct {
+	DB *sql.DB
+	// Query returns the SELECT statement for the condition, without ORDER BY, LIMIT and OFFSET, and its args.
+	Query func(cond interface{}) (query string, args []interface{})
+	// Scan scans the current row into a record.
+	Scan func(row Row) (interface{}, error)
+	// Columns maps column names of pagination.Order to SQL expressions.
+	// Orders of other columns are rejected.
+	Columns map[string]string
+	// TxOptions are options of the snapshot. Read-only repeatable read is used if nil.
+	TxOptions *sql.TxOptions
+	// UnionRanges fetches the active chunk, the first and the last pages with a single UNION ALL query.
+	// Args of Query are repeated for each range, so placeholders must not be numbered like $1.
+	// Each range is numbered with ROW_NUMBER() outside the query, so the database must support window functions,
+	// and Columns must map to column names of the query result.
+	UnionRanges bool
+}
+
+// Row is the current row of *sql.Rows.
+type Row interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryer is *sql.DB or *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Count counts records of the condition.
+func (f *Fetcher) Count(cond interface{}) (int, error) {
+	return f.count(context.Background(), f.DB, cond)
+}
+
+// FetchPage fetches records of the condition in the range of input.
+func (f *Fetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	return f.fetchPage(context.Background(), f.DB, cond, input, result)
+}
+
+// FetchRanges fetches records of the condition in all ranges of inputs into results.
+func (f *Fetcher) FetchRanges(cond interface{}, inputs []*pagination.PageFetchInput, results []*pagination.PageFetchResult) error {
+	return f.fetchRanges(context.Background(), f.DB, cond, inputs, results)
+}
+
+// Begin starts a read-only snapshot.
+func (f *Fetcher) Begin(ctx context.Context) (pagination.PageFetcherTx, error) {
+	opts := f.TxOptions
+	if opts == nil {
+		opts = &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	}
+	tx, err := f.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &fetcherTx{f: f, ctx: ctx, tx: tx}, nil
+}
+
+func (f *Fetcher) count(ctx context.Context, q queryer, cond interface{}) (int, error) {
+	query, args := f.Query(cond)
+	var count int
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+query+") AS pagination_count", args...).Scan(&count)
+	return count, err
+}
+
+func (f *Fetcher) fetchPage(ctx context.Context, q queryer, cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	query, args := f.Query(cond)
+	orderBy, err := f.orderBy(input.Orders)
+	if err != nil {
+		return err
+	}
+
+	rows, err := q.QueryContext(ctx, query+orderBy+limitOffset(input.Limit, input.Offset), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	_, err = f.scan(rows, rows, result)
+	return err
+}
+
+func (f *Fetcher) fetchRanges(ctx context.Context, q queryer, cond interface{}, inputs []*pagination.PageFetchInput, results []*pagination.PageFetchResult) error {
+	if !f.UnionRanges {
+		for i, input := range inputs {
+			err := f.fetchPage(ctx, q, cond, input, results[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	query, args := f.Query(cond)
+	selects := make([]string, 0, len(inputs))
+	unionArgs := make([]interface{}, 0, len(args)*len(inputs))
+	for i, input := range inputs {
+		orderBy, err := f.orderBy(input.Orders)
+		if err != nil {
+			return err
+		}
+		// each range is a derived table, since ORDER BY and LIMIT of a UNION member need parentheses in some databases.
+		// UNION ALL keeps no order of members, so rows have the index of the range and the row number in it.
+		selects = append(selects, fmt.Sprintf(
+			"SELECT * FROM (SELECT pagination_page.*, %d AS pagination_range, ROW_NUMBER() OVER (%s) AS pagination_row FROM (%s) AS pagination_page%s%s) AS pagination_range_%d",
+			i, strings.TrimPrefix(orderBy, " "), query, orderBy, limitOffset(input.Limit, input.Offset), i,
+		))
+		unionArgs = append(unionArgs, args...)
+	}
+
+	rows, err := q.QueryContext(ctx, strings.Join(selects, " UNION ALL ")+" ORDER BY pagination_range, pagination_row", unionArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	row := &rangedRow{rows: rows}
+	for rows.Next() {
+		record, err := f.Scan(row)
+		if err != nil {
+			return err
+		}
+		if row.index < 0 || row.index >= len(results) {
+			return fmt.Errorf("sqlfetcher: unknown range %d", row.index)
+		}
+		*results[row.index] = append(*results[row.index], record)
+	}
+	return rows.Err()
+}
+
+// rangedRow scans the index of the range and the row number in the last columns.
+type rangedRow struct {
+	rows   Row
+	index  int
+	number int64
+}
+
+func (r *rangedRow) Scan(dest ...interface{}) error {
+	return r.rows.Scan(append(dest, &r.index, &r.number)...)
+}
+
+// scan scans all rows into records, and returns the count of them.
+func (f *Fetcher) scan(rows *sql.Rows, row Row, result *pagination.PageFetchResult) (int, error) {
+	n := 0
+	for rows.Next() {
+		record, err := f.Scan(row)
+		if err != nil {
+			return n, err
+		}
+		*result = append(*result, record)
+		n++
+	}
+	return n, rows.Err()
+}
+
+// orderBy returns ORDER BY clause of the orders.
+func (f *Fetcher) orderBy(orders []*pagination.Order) (string, error) {
+	if len(orders) == 0 {
+		return "", nil
+	}
+	clauses := make([]string, 0, len(orders))
+	for _, o := range orders {
+		expr, ok := f.Columns[o.ColumnName]
+		if !ok {
+			return "", fmt.Errorf("sqlfetcher: unknown sort column %q", o.ColumnName)
+		}
+		d := pagination.DirectionAsc
+		if o.Direction == pagination.DirectionDesc {
+			d = pagination.DirectionDesc
+		}
+		clauses = append(clauses, expr+" "+string(d))
+	}
+	return " ORDER BY " + strings.Join(clauses, ", "), nil
+}
+
+func limitOffset(limit, offset int) string {
+	return fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+}
+
+// fetcherTx is Fetcher bound to a transaction.
+type fetcherTx struct {
+	f   *Fetcher
+	ctx context.Context
+	tx  *sql.Tx
+}
+
+func (t *fetcherTx) Count(cond interface{}) (int, error) {
+	return t.f.count(t.ctx, t.tx, cond)
+}
+
+func (t *fetcherTx) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	return t.f.fetchPage(t.ctx, t.tx, cond, input, result)
+}
+
+func (t *fetcherTx) FetchRanges(cond interface{}, inputs []*pagination.PageFetchInput, results []*pagination.PageFetchResult) error {
+	return t.f.fetchRanges(t.ctx, t.tx, cond, inputs, results)
+}
+
+func (t *fetcherTx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *fetcherTx) Rollback() error {
+	return t.tx.Rollback()
+}
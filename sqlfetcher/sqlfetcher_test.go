@@ -0,0 +1,186 @@
+package sqlfetcher_test
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/sqlfetcher"
+)
+
+var dummyFruits = []fruit{
+	{"Apple", 112},
+	{"Pear", 245},
+	{"Banana", 60},
+	{"Orange", 80},
+	{"Kiwi", 106},
+	{"Strawberry", 350},
+	{"Grape", 400},
+	{"Grapefruit", 150},
+	{"Pineapple", 200},
+	{"Cherry", 140},
+	{"Mango", 199},
+}
+
+func newFruitFetcher(db *sql.DB) *sqlfetcher.Fetcher {
+	return &sqlfetcher.Fetcher{
+		DB: db,
+		Query: func(cond interface{}) (string, []interface{}) {
+			if low, ok := cond.(int); ok {
+				return "SELECT name, price FROM fruits WHERE price >= ?", []interface{}{int64(low)}
+			}
+			return "SELECT name, price FROM fruits", nil
+		},
+		Scan: func(row sqlfetcher.Row) (interface{}, error) {
+			var f fruit
+			err := row.Scan(&f.Name, &f.Price)
+			return f, err
+		},
+		Columns: map[string]string{"price": "price", "name": "fruits.name"},
+	}
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	db, fdb := openFakeDB(t.Name(), dummyFruits)
+	defer db.Close()
+
+	totalCount, pageCount, res, err := pagination.Fetch(newFruitFetcher(db), &pagination.Setting{
+		Limit:  2,
+		Page:   5,
+		Cond:   100,
+		Orders: []*pagination.Order{{Direction: pagination.DirectionDesc, ColumnName: "price"}, {Direction: pagination.DirectionAsc, ColumnName: "name"}},
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if totalCount != 9 || pageCount != 5 {
+		t.Errorf("Fetch() = %v, %v, want 9, 5", totalCount, pageCount)
+	}
+	wantActive := pagination.PageFetchResult{fruit{"Mango", 199}}
+	if !reflect.DeepEqual(res.Pages["last"], wantActive) {
+		t.Errorf("Fetch() pages.last = %v, want %v", res.Pages["last"], wantActive)
+	}
+
+	want := []string{
+		"BEGIN isolation=Repeatable Read read_only=true",
+		"SELECT COUNT(*) FROM (SELECT name, price FROM fruits WHERE price >= ?) AS pagination_count [100]",
+		"SELECT name, price FROM fruits WHERE price >= ? ORDER BY price DESC, fruits.name ASC LIMIT 9 OFFSET 0 [100]",
+		"COMMIT",
+	}
+	if got := fdb.statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fetch() statements =\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFetcher_TxOptions(t *testing.T) {
+	db, fdb := openFakeDB(t.Name(), dummyFruits)
+	defer db.Close()
+
+	fetcher := newFruitFetcher(db)
+	fetcher.TxOptions = &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}
+	if _, _, _, err := pagination.Fetch(fetcher, &pagination.Setting{Limit: 20}); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if got := fdb.statements()[0]; got != "BEGIN isolation=Serializable read_only=true" {
+		t.Errorf("Fetch() begins %v", got)
+	}
+}
+
+func TestFetcher_Rollback(t *testing.T) {
+	db, fdb := openFakeDB(t.Name(), dummyFruits)
+	defer db.Close()
+	fdb.queryErr = "LIMIT"
+
+	_, _, _, err := pagination.Fetch(newFruitFetcher(db), &pagination.Setting{Limit: 2})
+	var fetcherErr *pagination.FetcherError
+	if !errors.As(err, &fetcherErr) || fetcherErr.Stage != pagination.StageRanges {
+		t.Errorf("Fetch() error = %v, want *FetcherError of ranges stage", err)
+	}
+	got := fdb.statements()
+	if got[len(got)-1] != "ROLLBACK" {
+		t.Errorf("Fetch() statements = %v, want ROLLBACK at last", got)
+	}
+}
+
+func TestFetcher_UnknownColumn(t *testing.T) {
+	db, _ := openFakeDB(t.Name(), dummyFruits)
+	defer db.Close()
+
+	result := pagination.PageFetchResult{}
+	err := newFruitFetcher(db).FetchPage(nil, &pagination.PageFetchInput{
+		Limit:  2,
+		Orders: []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: "price; DROP TABLE fruits"}},
+	}, &result)
+	if err == nil || !strings.Contains(err.Error(), "unknown sort column") {
+		t.Errorf("FetchPage() error = %v, want unknown sort column", err)
+	}
+}
+
+func TestFetcher_WithoutSnapshot(t *testing.T) {
+	db, fdb := openFakeDB(t.Name(), dummyFruits)
+	defer db.Close()
+
+	fetcher := newFruitFetcher(db)
+	count, err := fetcher.Count(nil)
+	if err != nil || count != 11 {
+		t.Errorf("Count() = %v, %v, want 11", count, err)
+	}
+	result := pagination.PageFetchResult{}
+	if err := fetcher.FetchPage(nil, &pagination.PageFetchInput{Limit: 2, Offset: 10}, &result); err != nil {
+		t.Fatalf("FetchPage() error = %v", err)
+	}
+	if !reflect.DeepEqual(result, pagination.PageFetchResult{fruit{"Mango", 199}}) {
+		t.Errorf("FetchPage() = %v", result)
+	}
+	for _, s := range fdb.statements() {
+		if strings.HasPrefix(s, "BEGIN") {
+			t.Errorf("Count() and FetchPage() must not begin snapshots: %v", s)
+		}
+	}
+}
+
+func TestFetcher_UnionRanges(t *testing.T) {
+	db, fdb := openFakeDB(t.Name(), dummyFruits)
+	defer db.Close()
+
+	fetcher := newFruitFetcher(db)
+	fetcher.UnionRanges = true
+	fetcher.Columns = map[string]string{"price": "price", "name": "name"}
+	_, _, res, err := pagination.Fetch(fetcher, &pagination.Setting{
+		Limit:  1,
+		Page:   5,
+		Cond:   100,
+		Orders: []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: "name"}},
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	want := pagination.Pages{
+		"first":          {fruit{"Apple", 112}},
+		"before_distant": {fruit{"Kiwi", 106}},
+		"before_near":    {fruit{"Strawberry", 350}},
+		"active":         {fruit{"Grape", 400}},
+		"after_near":     {fruit{"Grapefruit", 150}},
+		"after_distant":  {fruit{"Pineapple", 200}},
+		"last":           {fruit{"Mango", 199}},
+	}
+	if !reflect.DeepEqual(res.Pages, want) {
+		t.Errorf("Fetch() pages = %v, want %v", res.Pages, want)
+	}
+
+	wantStatements := []string{
+		"BEGIN isolation=Repeatable Read read_only=true",
+		"SELECT COUNT(*) FROM (SELECT name, price FROM fruits WHERE price >= ?) AS pagination_count [100]",
+		"SELECT * FROM (SELECT pagination_page.*, 0 AS pagination_range, ROW_NUMBER() OVER (ORDER BY name ASC) AS pagination_row FROM (SELECT name, price FROM fruits WHERE price >= ?) AS pagination_page ORDER BY name ASC LIMIT 5 OFFSET 2) AS pagination_range_0" +
+			" UNION ALL SELECT * FROM (SELECT pagination_page.*, 1 AS pagination_range, ROW_NUMBER() OVER (ORDER BY name ASC) AS pagination_row FROM (SELECT name, price FROM fruits WHERE price >= ?) AS pagination_page ORDER BY name ASC LIMIT 1 OFFSET 0) AS pagination_range_1" +
+			" UNION ALL SELECT * FROM (SELECT pagination_page.*, 2 AS pagination_range, ROW_NUMBER() OVER (ORDER BY name ASC) AS pagination_row FROM (SELECT name, price FROM fruits WHERE price >= ?) AS pagination_page ORDER BY name ASC LIMIT 1 OFFSET 8) AS pagination_range_2" +
+			" ORDER BY pagination_range, pagination_row [100 100 100]",
+		"COMMIT",
+	}
+	if got := fdb.statements(); !reflect.DeepEqual(got, wantStatements) {
+		t.Errorf("Fetch() statements =\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(wantStatements, "\n"))
+	}
+}
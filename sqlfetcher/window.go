@@ -0,0 +1,74 @@
+package sqlfetcher
+
+import (
+	"context"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// WindowFetcher is Fetcher which counts records with COUNT(*) OVER() in the query of the active pages,
+// so Fetch needs no separate COUNT query. The database must support window functions.
+// The query is wrapped in a subquery, so Columns must map to column names of the query result.
+type WindowFetcher struct {
+	Fetcher
+}
+
+// FetchPageWithCount fetches records of the condition in the range of input, and counts all records of the condition.
+func (f *WindowFetcher) FetchPageWithCount(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) (int, error) {
+	return f.fetchPageWithCount(context.Background(), f.DB, cond, input, result)
+}
+
+// Begin starts a read-only snapshot.
+func (f *WindowFetcher) Begin(ctx context.Context) (pagination.PageFetcherTx, error) {
+	tx, err := f.Fetcher.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &windowFetcherTx{fetcherTx: tx.(*fetcherTx), f: f}, nil
+}
+
+func (f *WindowFetcher) fetchPageWithCount(ctx context.Context, q queryer, cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) (int, error) {
+	query, args := f.Query(cond)
+	orderBy, err := f.orderBy(input.Orders)
+	if err != nil {
+		return 0, err
+	}
+
+	query = "SELECT pagination_page.*, COUNT(*) OVER() AS pagination_count FROM (" + query + ") AS pagination_page"
+	rows, err := q.QueryContext(ctx, query+orderBy+limitOffset(input.Limit, input.Offset), args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	row := &countedRow{rows: rows}
+	n, err := f.scan(rows, row, result)
+	if err != nil {
+		return 0, err
+	}
+	// no row has the count, when the offset is beyond the last record
+	if n == 0 && input.Offset > 0 {
+		return f.count(ctx, q, cond)
+	}
+	return row.count, nil
+}
+
+// countedRow scans the count of the window function in the last column.
+type countedRow struct {
+	rows  Row
+	count int
+}
+
+func (r *countedRow) Scan(dest ...interface{}) error {
+	return r.rows.Scan(append(dest, &r.count)...)
+}
+
+// windowFetcherTx is WindowFetcher bound to a transaction.
+type windowFetcherTx struct {
+	*fetcherTx
+	f *WindowFetcher
+}
+
+func (t *windowFetcherTx) FetchPageWithCount(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) (int, error) {
+	return t.f.fetchPageWithCount(t.ctx, t.tx, cond, input, result)
+}
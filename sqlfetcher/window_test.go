@@ -0,0 +1,79 @@
+package sqlfetcher_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/sqlfetcher"
+)
+
+func TestWindowFetcher_Fetch(t *testing.T) {
+	tests := []struct {
+		name       string
+		page       int
+		wantActive pagination.PageFetchResult
+		want       []string
+	}{
+		{"counted chunk", 2, pagination.PageFetchResult{fruit{"Kiwi", 106}, fruit{"Strawberry", 350}}, []string{
+			"BEGIN isolation=Repeatable Read read_only=true",
+			"SELECT pagination_page.*, COUNT(*) OVER() AS pagination_count FROM (SELECT name, price FROM fruits WHERE price >= ?) AS pagination_page ORDER BY price DESC LIMIT 10 OFFSET 0 [100]",
+			"COMMIT",
+		}},
+		{"chunk near the last page", 5, pagination.PageFetchResult{fruit{"Mango", 199}}, []string{
+			"BEGIN isolation=Repeatable Read read_only=true",
+			"SELECT pagination_page.*, COUNT(*) OVER() AS pagination_count FROM (SELECT name, price FROM fruits WHERE price >= ?) AS pagination_page ORDER BY price DESC LIMIT 10 OFFSET 4 [100]",
+			"SELECT name, price FROM fruits WHERE price >= ? ORDER BY price DESC LIMIT 9 OFFSET 0 [100]",
+			"COMMIT",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fdb := openFakeDB(t.Name(), dummyFruits)
+			defer db.Close()
+
+			fetcher := &sqlfetcher.WindowFetcher{Fetcher: *newFruitFetcher(db)}
+			totalCount, pageCount, res, err := pagination.Fetch(fetcher, &pagination.Setting{
+				Limit:  2,
+				Page:   tt.page,
+				Cond:   100,
+				Orders: []*pagination.Order{{Direction: pagination.DirectionDesc, ColumnName: "price"}},
+			})
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if totalCount != 9 || pageCount != 5 {
+				t.Errorf("Fetch() = %v, %v, want 9, 5", totalCount, pageCount)
+			}
+			if !reflect.DeepEqual(res.Pages["active"], tt.wantActive) {
+				t.Errorf("Fetch() pages.active = %v, want %v", res.Pages["active"], tt.wantActive)
+			}
+			if got := fdb.statements(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fetch() statements =\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestWindowFetcher_FetchPageWithCount(t *testing.T) {
+	db, fdb := openFakeDB(t.Name(), dummyFruits)
+	defer db.Close()
+
+	fetcher := &sqlfetcher.WindowFetcher{Fetcher: *newFruitFetcher(db)}
+	result := pagination.PageFetchResult{}
+	count, err := fetcher.FetchPageWithCount(nil, &pagination.PageFetchInput{Limit: 2, Offset: 20}, &result)
+	if err != nil {
+		t.Fatalf("FetchPageWithCount() error = %v", err)
+	}
+	if count != 11 || len(result) != 0 {
+		t.Errorf("FetchPageWithCount() = %v, %v, want 11 and no records", count, result)
+	}
+	want := []string{
+		"SELECT pagination_page.*, COUNT(*) OVER() AS pagination_count FROM (SELECT name, price FROM fruits) AS pagination_page LIMIT 2 OFFSET 20 []",
+		"SELECT COUNT(*) FROM (SELECT name, price FROM fruits) AS pagination_count []",
+	}
+	if got := fdb.statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchPageWithCount() statements =\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
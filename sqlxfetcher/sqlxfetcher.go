@@ -0,0 +1,90 @@
+// Package sqlxfetcher provides pagination.PageFetcher of sqlx and squirrel.
+package sqlxfetcher
+
+import (
+	"fmt"
+	"reflect"
+
+	sq "github.com/Masterminds/squirrel"
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/jmoiron/sqlx"
+	"github.com/lann/builder"
+)
+
+// Fetcher is pagination.PageFetcher of a squirrel.SelectBuilder.
+type Fetcher struct {
+	DB *sqlx.DB
+	// Select returns the query of the condition.
+	// Its placeholders are rebound for the driver of DB, so they must be of squirrel.Question.
+	Select func(cond interface{}) sq.SelectBuilder
+	// Records is the slice type which records are scanned into with sqlx.Select, e.g. []Fruit{}.
+	// Each element is appended to pagination.PageFetchResult.
+	Records interface{}
+	// Columns maps column names of pagination.Order to SQL expressions.
+	// Orders of other columns are rejected.
+	Columns map[string]string
+}
+
+// Count counts records of the condition.
+func (f *Fetcher) Count(cond interface{}) (int, error) {
+	query, args, err := CountQuery(f.Select(cond)).ToSql()
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	err = f.DB.Get(&count, f.DB.Rebind(query), args...)
+	return count, err
+}
+
+// FetchPage fetches records of the condition in the range of input.
+func (f *Fetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	b := f.Select(cond)
+	if len(input.Orders) > 0 {
+		b = builder.Delete(b, "OrderByParts").(sq.SelectBuilder)
+	}
+	for _, o := range input.Orders {
+		expr, ok := f.Columns[o.ColumnName]
+		if !ok {
+			return fmt.Errorf("sqlxfetcher: unknown sort column %q", o.ColumnName)
+		}
+		d := pagination.DirectionAsc
+		if o.Direction == pagination.DirectionDesc {
+			d = pagination.DirectionDesc
+		}
+		b = b.OrderBy(expr + " " + string(d))
+	}
+
+	query, args, err := b.Limit(uint64(input.Limit)).Offset(uint64(input.Offset)).ToSql()
+	if err != nil {
+		return err
+	}
+	records := reflect.New(reflect.TypeOf(f.Records))
+	err = sqlx.Select(f.DB, records.Interface(), f.DB.Rebind(query), args...)
+	if err != nil {
+		return err
+	}
+	records = records.Elem()
+	for i := 0; i < records.Len(); i++ {
+		*result = append(*result, records.Index(i).Interface())
+	}
+	return nil
+}
+
+// CountQuery returns the query which counts records of the select builder.
+// The columns of the builder are replaced by COUNT(*),
+// or the builder is wrapped as a subquery when it has GROUP BY or DISTINCT, whose rows are not the records.
+func CountQuery(b sq.SelectBuilder) sq.SelectBuilder {
+	b = builder.Delete(b, "OrderByParts").(sq.SelectBuilder)
+	b = b.RemoveLimit().RemoveOffset()
+
+	if hasParts(b, "GroupBys") || hasParts(b, "HavingParts") || hasParts(b, "Options") {
+		return sq.Select("COUNT(*)").FromSelect(b, "pagination_count")
+	}
+	return b.RemoveColumns().Columns("COUNT(*)")
+}
+
+// hasParts reports whether the builder has the parts of the name.
+func hasParts(b sq.SelectBuilder, name string) bool {
+	parts, ok := builder.Get(b, name)
+	return ok && reflect.ValueOf(parts).Len() > 0
+}
@@ -0,0 +1,175 @@
+package sqlxfetcher_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	pagination "github.com/gemcook/pagination-go"
+	"github.com/gemcook/pagination-go/paginationtest"
+	"github.com/gemcook/pagination-go/sqlxfetcher"
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+type fruit struct {
+	Name  string `db:"name"`
+	Price int    `db:"price"`
+	Color string `db:"color"`
+}
+
+var dummyFruits = []fruit{
+	{"Apple", 112, "red"},
+	{"Pear", 245, "green"},
+	{"Banana", 60, "yellow"},
+	{"Orange", 80, "orange"},
+	{"Kiwi", 106, "green"},
+	{"Strawberry", 350, "red"},
+	{"Grape", 400, "purple"},
+	{"Grapefruit", 150, "yellow"},
+	{"Pineapple", 200, "yellow"},
+	{"Cherry", 140, "red"},
+	{"Mango", 199, "yellow"},
+}
+
+func openDB(t *testing.T) *sqlx.DB {
+	db, err := sqlx.Open("sqlite", filepath.Join(t.TempDir(), "fruits.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.MustExec("CREATE TABLE fruits (name TEXT, price INTEGER, color TEXT)")
+	for _, f := range dummyFruits {
+		db.MustExec("INSERT INTO fruits (name, price, color) VALUES (?, ?, ?)", f.Name, f.Price, f.Color)
+	}
+	return db
+}
+
+func names(records pagination.PageFetchResult) []string {
+	names := []string{}
+	for _, r := range records {
+		names = append(names, r.(fruit).Name)
+	}
+	return names
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	db := openDB(t)
+	fetcher := &sqlxfetcher.Fetcher{
+		DB: db,
+		Select: func(cond interface{}) sq.SelectBuilder {
+			return sq.Select("name", "price", "color").From("fruits").Where(sq.GtOrEq{"price": cond}).OrderBy("name")
+		},
+		Records: []fruit{},
+		Columns: map[string]string{"price": "price"},
+	}
+
+	totalCount, pageCount, res, err := pagination.Fetch(fetcher, &pagination.Setting{
+		Limit:  2,
+		Page:   2,
+		Cond:   100,
+		Orders: []*pagination.Order{{Direction: pagination.DirectionDesc, ColumnName: "price"}},
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if totalCount != 9 || pageCount != 5 {
+		t.Errorf("Fetch() = %v, %v, want 9, 5", totalCount, pageCount)
+	}
+	want := map[string][]string{
+		"first":  {"Grape", "Strawberry"},
+		"active": {"Pear", "Pineapple"},
+		"last":   {"Kiwi"},
+	}
+	for name, want := range want {
+		if got := names(res.Pages[name]); !reflect.DeepEqual(got, want) {
+			t.Errorf("Fetch() pages.%v = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFetcher_Suite(t *testing.T) {
+	paginationtest.RunFetcherSuite(t, func(t *testing.T) *paginationtest.Fixture {
+		return &paginationtest.Fixture{
+			Fetcher: &sqlxfetcher.Fetcher{
+				DB: openDB(t),
+				Select: func(cond interface{}) sq.SelectBuilder {
+					return sq.Select("name", "price", "color").From("fruits").Where(sq.GtOrEq{"price": cond})
+				},
+				Records: []fruit{},
+				Columns: map[string]string{"price": "price"},
+			},
+			Cond:      100,
+			EmptyCond: 1000,
+			Orders:    []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: "price"}},
+		}
+	})
+}
+
+func TestFetcher_Count(t *testing.T) {
+	tests := []struct {
+		name string
+		b    sq.SelectBuilder
+		want int
+	}{
+		{"plain", sq.Select("name").From("fruits").Where("price >= ?", 100).OrderBy("name").Limit(3), 9},
+		{"group by", sq.Select("color", "COUNT(*)").From("fruits").GroupBy("color"), 5},
+		{"having", sq.Select("color").From("fruits").GroupBy("color").Having("COUNT(*) > ?", 1), 3},
+		{"distinct", sq.Select("color").Distinct().From("fruits").Where("price < ?", 200), 4},
+	}
+	db := openDB(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := &sqlxfetcher.Fetcher{
+				DB:     db,
+				Select: func(cond interface{}) sq.SelectBuilder { return tt.b },
+			}
+			count, err := fetcher.Count(nil)
+			if err != nil || count != tt.want {
+				t.Errorf("Count() = %v, %v, want %v", count, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		b    sq.SelectBuilder
+		want string
+	}{
+		{"plain", sq.Select("name", "price").From("fruits").Where("price >= ?", 100).OrderBy("name").Limit(3).Offset(6),
+			"SELECT COUNT(*) FROM fruits WHERE price >= ?"},
+		{"group by", sq.Select("color").From("fruits").GroupBy("color").OrderBy("color"),
+			"SELECT COUNT(*) FROM (SELECT color FROM fruits GROUP BY color) AS pagination_count"},
+		{"distinct", sq.Select("color").Distinct().From("fruits"),
+			"SELECT COUNT(*) FROM (SELECT DISTINCT color FROM fruits) AS pagination_count"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := sqlxfetcher.CountQuery(tt.b).ToSql()
+			if err != nil || got != tt.want {
+				t.Errorf("CountQuery() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetcher_UnknownColumn(t *testing.T) {
+	fetcher := &sqlxfetcher.Fetcher{
+		DB:      openDB(t),
+		Select:  func(cond interface{}) sq.SelectBuilder { return sq.Select("*").From("fruits") },
+		Records: []fruit{},
+		Columns: map[string]string{"price": "price"},
+	}
+	result := pagination.PageFetchResult{}
+	err := fetcher.FetchPage(nil, &pagination.PageFetchInput{
+		Limit:  2,
+		Orders: []*pagination.Order{{Direction: pagination.DirectionAsc, ColumnName: "price; DROP TABLE fruits"}},
+	}, &result)
+	if err == nil || !strings.Contains(err.Error(), "unknown sort column") {
+		t.Errorf("FetchPage() error = %v, want unknown sort column", err)
+	}
+}
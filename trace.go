@@ -0,0 +1,117 @@
+package pagination
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Tracer starts spans of Count and FetchPage, like OpenTelemetry's trace.Tracer.
+// ctx is the context of FetchContext, so spans can be children of the request span.
+// Wrap your tracer to use it with TraceHooks.
+type Tracer interface {
+	StartSpan(ctx context.Context, name string, start time.Time) Span
+}
+
+// Span is a span of Count or FetchPage, like OpenTelemetry's trace.Span.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End(end time.Time)
+}
+
+// TraceHooks returns hooks which record spans named "pagination.count" and "pagination.fetch.<stage>".
+// Spans are started after the call with its start time, so the hooks can be shared by concurrent fetches.
+func TraceHooks(tracer Tracer) *Hooks {
+	return &Hooks{
+		AfterCount: func(ctx context.Context, cond interface{}, count int, elapsed time.Duration) {
+			span := startSpan(ctx, tracer, StageCount, elapsed)
+			span.SetAttribute("pagination.count", count)
+			span.End(time.Now())
+		},
+		AfterFetch: func(ctx context.Context, stage Stage, input *PageFetchInput, rows int, elapsed time.Duration) {
+			span := startSpan(ctx, tracer, stage, elapsed)
+			setInputAttributes(span, input)
+			span.SetAttribute("pagination.rows", rows)
+			span.End(time.Now())
+		},
+		OnError: func(ctx context.Context, stage Stage, input *PageFetchInput, err error, elapsed time.Duration) {
+			span := startSpan(ctx, tracer, stage, elapsed)
+			if input != nil {
+				setInputAttributes(span, input)
+			}
+			span.RecordError(err)
+			span.End(time.Now())
+		},
+	}
+}
+
+func startSpan(ctx context.Context, tracer Tracer, stage Stage, elapsed time.Duration) Span {
+	name := "pagination.fetch." + string(stage)
+	if stage == StageCount {
+		name = "pagination.count"
+	}
+	span := tracer.StartSpan(ctx, name, time.Now().Add(-elapsed))
+	span.SetAttribute("pagination.stage", string(stage))
+	return span
+}
+
+func setInputAttributes(span Span, input *PageFetchInput) {
+	span.SetAttribute("pagination.limit", input.Limit)
+	span.SetAttribute("pagination.offset", input.Offset)
+}
+
+// SpanRecorder is an in-memory Tracer, which is useful for tests.
+type SpanRecorder struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// RecordedSpan is a span recorded by SpanRecorder.
+type RecordedSpan struct {
+	Name       string
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes map[string]interface{}
+	Err        error
+}
+
+// NewSpanRecorder returns an empty SpanRecorder.
+func NewSpanRecorder() *SpanRecorder {
+	return &SpanRecorder{}
+}
+
+// StartSpan records a new span.
+func (r *SpanRecorder) StartSpan(ctx context.Context, name string, start time.Time) Span {
+	span := &RecordedSpan{
+		Name:       name,
+		StartTime:  start,
+		Attributes: map[string]interface{}{},
+	}
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+	return span
+}
+
+// Spans returns the recorded spans in started order.
+func (r *SpanRecorder) Spans() []*RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*RecordedSpan{}, r.spans...)
+}
+
+// SetAttribute sets the attribute of the span.
+func (s *RecordedSpan) SetAttribute(key string, value interface{}) {
+	s.Attributes[key] = value
+}
+
+// RecordError sets the error of the span.
+func (s *RecordedSpan) RecordError(err error) {
+	s.Err = err
+}
+
+// End sets the end time of the span.
+func (s *RecordedSpan) End(end time.Time) {
+	s.EndTime = end
+}
@@ -0,0 +1,93 @@
+package pagination_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+func TestTraceHooks(t *testing.T) {
+	recorder := pagination.NewSpanRecorder()
+	_, _, _, err := pagination.Fetch(newLargeDataFetcher(), &pagination.Setting{
+		Limit: 10,
+		Page:  5,
+		Hooks: pagination.TraceHooks(recorder),
+	})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	want := []struct {
+		name       string
+		attributes map[string]interface{}
+	}{
+		{"pagination.count", map[string]interface{}{"pagination.stage": "count", "pagination.count": 103}},
+		{"pagination.fetch.active", map[string]interface{}{"pagination.stage": "active", "pagination.limit": 50, "pagination.offset": 20, "pagination.rows": 50}},
+		{"pagination.fetch.first", map[string]interface{}{"pagination.stage": "first", "pagination.limit": 10, "pagination.offset": 0, "pagination.rows": 10}},
+		{"pagination.fetch.last", map[string]interface{}{"pagination.stage": "last", "pagination.limit": 10, "pagination.offset": 100, "pagination.rows": 3}},
+	}
+	spans := recorder.Spans()
+	if len(spans) != len(want) {
+		t.Fatalf("TraceHooks() spans = %v, want %v spans", len(spans), len(want))
+	}
+	for i, span := range spans {
+		if span.Name != want[i].name {
+			t.Errorf("TraceHooks() spans[%v].Name = %v, want %v", i, span.Name, want[i].name)
+		}
+		if !reflect.DeepEqual(span.Attributes, want[i].attributes) {
+			t.Errorf("TraceHooks() spans[%v].Attributes = %v, want %v", i, span.Attributes, want[i].attributes)
+		}
+		if span.EndTime.Before(span.StartTime) {
+			t.Errorf("TraceHooks() spans[%v] ends before start: %v - %v", i, span.StartTime, span.EndTime)
+		}
+		if span.Err != nil {
+			t.Errorf("TraceHooks() spans[%v].Err = %v", i, span.Err)
+		}
+	}
+}
+
+func TestTraceHooks_Error(t *testing.T) {
+	recorder := pagination.NewSpanRecorder()
+	pagination.Fetch(&failingFetcher{}, &pagination.Setting{Limit: 10, Hooks: pagination.TraceHooks(recorder)})
+
+	spans := recorder.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("TraceHooks() spans = %v, want 2 spans", len(spans))
+	}
+	if spans[1].Name != "pagination.fetch.active" || spans[1].Err == nil || spans[1].Err.Error() != "connection reset" {
+		t.Errorf("TraceHooks() spans[1] = %+v, want the error of active chunk", spans[1])
+	}
+}
+
+type traceKey struct{}
+
+// contextTracer records the trace values of span contexts.
+type contextTracer struct {
+	*pagination.SpanRecorder
+	traces []interface{}
+}
+
+func (ct *contextTracer) StartSpan(ctx context.Context, name string, start time.Time) pagination.Span {
+	ct.traces = append(ct.traces, ctx.Value(traceKey{}))
+	return ct.SpanRecorder.StartSpan(ctx, name, start)
+}
+
+func TestTraceHooks_Context(t *testing.T) {
+	tracer := &contextTracer{SpanRecorder: pagination.NewSpanRecorder()}
+	ctx := context.WithValue(context.Background(), traceKey{}, "request-span")
+	_, _, _, err := pagination.FetchContext(ctx, newLargeDataFetcher(), &pagination.Setting{
+		Limit: 10,
+		Page:  5,
+		Hooks: pagination.TraceHooks(tracer),
+	})
+	if err != nil {
+		t.Fatalf("FetchContext() error = %v", err)
+	}
+	want := []interface{}{"request-span", "request-span", "request-span", "request-span"}
+	if !reflect.DeepEqual(tracer.traces, want) {
+		t.Errorf("StartSpan() contexts = %v, want %v", tracer.traces, want)
+	}
+}
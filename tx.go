@@ -0,0 +1,44 @@
+package pagination
+
+import "context"
+
+// TxPageFetcher is a PageFetcher which can run Count and FetchPage in a single read-only snapshot.
+// Pager begins a snapshot before Count, and commits it after all pages are fetched,
+// so the total count always agrees with the fetched records.
+type TxPageFetcher interface {
+	PageFetcher
+	// Begin starts a read-only snapshot, and returns the fetcher bound to it.
+	Begin(ctx context.Context) (PageFetcherTx, error)
+}
+
+// PageFetcherTx is a PageFetcher bound to a snapshot.
+type PageFetcherTx interface {
+	PageFetcher
+	Commit() error
+	Rollback() error
+}
+
+// getPagesInTx gets pages in a snapshot of the fetcher.
+func (p *Pager) getPagesInTx(fetcher TxPageFetcher) (*PagingResponse, error) {
+	tx, err := fetcher.Begin(p.ctx)
+	if err != nil {
+		return nil, &FetcherError{Stage: StageBegin, Err: err}
+	}
+
+	p.fetcher = tx
+	p.inSnapshot = true
+	defer func() {
+		p.fetcher = fetcher
+		p.inSnapshot = false
+	}()
+
+	res, err := p.getPages()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, &FetcherError{Stage: StageCommit, Err: err}
+	}
+	return res, nil
+}
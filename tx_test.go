@@ -0,0 +1,101 @@
+package pagination_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// snapshotFetcher records calls made inside and outside of snapshots.
+type snapshotFetcher struct {
+	LargeDataFetcher
+	calls     []string
+	beginErr  error
+	commitErr error
+	fetchErr  error
+}
+
+func (sf *snapshotFetcher) Count(cond interface{}) (int, error) {
+	sf.calls = append(sf.calls, "count without snapshot")
+	return sf.LargeDataFetcher.Count(cond)
+}
+
+func (sf *snapshotFetcher) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	sf.calls = append(sf.calls, "fetch without snapshot")
+	return sf.LargeDataFetcher.FetchPage(cond, input, result)
+}
+
+func (sf *snapshotFetcher) Begin(ctx context.Context) (pagination.PageFetcherTx, error) {
+	if sf.beginErr != nil {
+		return nil, sf.beginErr
+	}
+	sf.calls = append(sf.calls, "begin")
+	return &snapshotFetcherTx{sf}, nil
+}
+
+type snapshotFetcherTx struct {
+	sf *snapshotFetcher
+}
+
+func (tx *snapshotFetcherTx) Count(cond interface{}) (int, error) {
+	tx.sf.calls = append(tx.sf.calls, "count")
+	return tx.sf.LargeDataFetcher.Count(cond)
+}
+
+func (tx *snapshotFetcherTx) FetchPage(cond interface{}, input *pagination.PageFetchInput, result *pagination.PageFetchResult) error {
+	tx.sf.calls = append(tx.sf.calls, "fetch")
+	if tx.sf.fetchErr != nil {
+		return tx.sf.fetchErr
+	}
+	return tx.sf.LargeDataFetcher.FetchPage(cond, input, result)
+}
+
+func (tx *snapshotFetcherTx) Commit() error {
+	tx.sf.calls = append(tx.sf.calls, "commit")
+	return tx.sf.commitErr
+}
+
+func (tx *snapshotFetcherTx) Rollback() error {
+	tx.sf.calls = append(tx.sf.calls, "rollback")
+	return nil
+}
+
+func TestFetch_TxPageFetcher(t *testing.T) {
+	errDB := errors.New("db is down")
+	tests := []struct {
+		name      string
+		fetcher   *snapshotFetcher
+		wantCalls []string
+		wantStage pagination.Stage
+	}{
+		{"snapshot", &snapshotFetcher{}, []string{"begin", "count", "fetch", "fetch", "fetch", "commit"}, ""},
+		{"begin error", &snapshotFetcher{beginErr: errDB}, []string{}, pagination.StageBegin},
+		{"fetch error", &snapshotFetcher{fetchErr: errDB}, []string{"begin", "count", "fetch", "rollback"}, pagination.StageActive},
+		{"commit error", &snapshotFetcher{commitErr: errDB}, []string{"begin", "count", "fetch", "fetch", "fetch", "commit"}, pagination.StageCommit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fetcher.calls = []string{}
+			totalCount, _, res, err := pagination.Fetch(tt.fetcher, &pagination.Setting{Limit: 10, Page: 5})
+			if !reflect.DeepEqual(tt.fetcher.calls, tt.wantCalls) {
+				t.Errorf("Fetch() calls = %q, want %q", tt.fetcher.calls, tt.wantCalls)
+			}
+			if tt.wantStage == "" {
+				if err != nil {
+					t.Fatalf("Fetch() error = %v", err)
+				}
+				if totalCount != 103 || len(res.Pages["active"]) != 10 {
+					t.Errorf("Fetch() = %v, %v, want 103 records", totalCount, res.Pages["active"])
+				}
+				return
+			}
+			var fetcherErr *pagination.FetcherError
+			if !errors.As(err, &fetcherErr) || fetcherErr.Stage != tt.wantStage || !errors.Is(err, errDB) {
+				t.Errorf("Fetch() error = %v, want *FetcherError of %v stage", err, tt.wantStage)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package pagination
+
+import (
+	"html/template"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// PageItem is an item of page navigation, a page number or a gap between page numbers.
+type PageItem struct {
+	// Number is the page number (1〜). It is 0 for gaps.
+	Number int
+	Active bool
+	Gap    bool
+}
+
+// Window returns items of page navigation like "1 … 4 5 [6] 7 8 … 20".
+// sides pages on each side of the active page, and boundaries pages at both ends are shown,
+// and the rest are gaps. A gap of a single page is shown as the page itself.
+func Window(page, pageCount, sides, boundaries int) []PageItem {
+	items := []PageItem{}
+	if pageCount <= 0 {
+		return items
+	}
+
+	// shown ranges of pages, so the items are built without visiting hidden pages
+	ranges := [][2]int{
+		{1, boundaries},
+		{page - sides, page + sides},
+		{pageCount - boundaries + 1, pageCount},
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+
+	last := 0
+	for _, r := range ranges {
+		from, to := r[0], r[1]
+		if from <= last {
+			from = last + 1
+		}
+		if to > pageCount {
+			to = pageCount
+		}
+		if from > to {
+			continue
+		}
+		items = appendHidden(items, last, from)
+		for n := from; n <= to; n++ {
+			items = append(items, PageItem{Number: n, Active: n == page})
+		}
+		last = to
+	}
+	return appendHidden(items, last, pageCount+1)
+}
+
+// appendHidden appends the item of pages hidden between the shown pages.
+func appendHidden(items []PageItem, shown, next int) []PageItem {
+	switch next - shown {
+	case 1:
+		return items
+	case 2:
+		// a single hidden page is shorter than a gap
+		return append(items, PageItem{Number: shown + 1})
+	}
+	return append(items, PageItem{Gap: true})
+}
+
+// PageURL returns the URL with the page parameter of DefaultParser replaced by the page number.
+// The offset parameter and aliases of the page parameter are removed, since they would win over or shadow the page.
+func PageURL(rawURL string, page int) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	name := DefaultParser.Page.Name
+	if name == "" {
+		name = "page"
+	}
+	q := u.Query()
+	deleteParam(q, DefaultParser.Offset)
+	deleteParam(q, DefaultParser.Page)
+	q.Set(name, strconv.Itoa(page))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// deleteParam deletes the parameter and its aliases from the query.
+func deleteParam(q url.Values, p Param) {
+	if p.Name != "" {
+		q.Del(p.Name)
+	}
+	for _, alias := range p.Aliases {
+		q.Del(alias)
+	}
+}
+
+// TemplateFuncs returns html/template functions for page navigation.
+//
+//	pageWindow page pageCount sides boundaries -> Window
+//	pageURL url page                           -> PageURL
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"pageWindow": Window,
+		"pageURL":    PageURL,
+	}
+}
@@ -0,0 +1,99 @@
+package pagination_test
+
+import (
+	"bytes"
+	"html/template"
+	"strconv"
+	"strings"
+	"testing"
+
+	pagination "github.com/gemcook/pagination-go"
+)
+
+// formatWindow formats page items like "1 … 4 5 [6] 7 8 … 20".
+func formatWindow(items []pagination.PageItem) string {
+	s := []string{}
+	for _, item := range items {
+		switch {
+		case item.Gap:
+			s = append(s, "…")
+		case item.Active:
+			s = append(s, "["+strconv.Itoa(item.Number)+"]")
+		default:
+			s = append(s, strconv.Itoa(item.Number))
+		}
+	}
+	return strings.Join(s, " ")
+}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name                               string
+		page, pageCount, sides, boundaries int
+		want                               string
+	}{
+		{"middle", 6, 20, 2, 1, "1 … 4 5 [6] 7 8 … 20"},
+		{"first", 1, 20, 2, 1, "[1] 2 3 … 20"},
+		{"last", 20, 20, 2, 1, "1 … 18 19 [20]"},
+		{"single hidden page", 5, 20, 2, 1, "1 2 3 4 [5] 6 7 … 20"},
+		{"two boundaries", 10, 20, 1, 2, "1 2 … 9 [10] 11 … 19 20"},
+		{"no boundaries", 10, 20, 2, 0, "… 8 9 [10] 11 12 …"},
+		{"all shown", 2, 5, 2, 1, "1 [2] 3 4 5"},
+		{"single page", 1, 1, 2, 1, "[1]"},
+		{"no pages", 1, 0, 2, 1, ""},
+		{"out of range", 30, 20, 2, 1, "1 … 20"},
+		{"near the last", 17, 20, 2, 1, "1 … 15 16 [17] 18 19 20"},
+		{"many pages", 5, 200000000, 2, 1, "1 2 3 4 [5] 6 7 … 200000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatWindow(pagination.Window(tt.page, tt.pageCount, tt.sides, tt.boundaries))
+			if got != tt.want {
+				t.Errorf("Window() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		rawURL string
+		page   int
+		want   string
+	}{
+		{"page", "/fruits?limit=10&page=2&sort=-price", 3, "/fruits?limit=10&page=3&sort=-price"},
+		{"no page", "/fruits?limit=10", 3, "/fruits?limit=10&page=3"},
+		{"offset", "/fruits?offset=40&limit=20", 5, "/fruits?limit=20&page=5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pagination.PageURL(tt.rawURL, tt.page)
+			if err != nil || got != tt.want {
+				t.Errorf("PageURL() = %v, %v, want %v", got, err, tt.want)
+			}
+			if q := pagination.ParseQuery(got); q.Page != tt.page {
+				t.Errorf("ParseQuery(PageURL()).Page = %v, want %v", q.Page, tt.page)
+			}
+		})
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	tmpl := template.Must(template.New("nav").Funcs(pagination.TemplateFuncs()).Parse(
+		`{{range pageWindow .Page .PageCount 1 1}}` +
+			`{{if .Gap}}<span>…</span>{{else if .Active}}<b>{{.Number}}</b>{{else}}<a href="{{pageURL $.URL .Number}}">{{.Number}}</a>{{end}}` +
+			`{{end}}`))
+
+	var b bytes.Buffer
+	err := tmpl.Execute(&b, map[string]interface{}{"Page": 5, "PageCount": 9, "URL": "/fruits?limit=5"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	want := `<a href="/fruits?limit=5&amp;page=1">1</a><span>…</span>` +
+		`<a href="/fruits?limit=5&amp;page=4">4</a><b>5</b><a href="/fruits?limit=5&amp;page=6">6</a>` +
+		`<span>…</span><a href="/fruits?limit=5&amp;page=9">9</a>`
+	if b.String() != want {
+		t.Errorf("Execute() =\n%v\nwant\n%v", b.String(), want)
+	}
+}